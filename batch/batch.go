@@ -0,0 +1,162 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Processor processes a single item of a batch.
+type Processor[I any] func(context.Context, I) error
+
+// Config is the configuration for batch processing.
+type Config struct {
+	// Concurrency is how many items, or ordering groups when Ordered is set, are processed at the same time.
+	// Defaults to 1.
+	Concurrency int
+
+	// Ordered processes items sharing the same ordering key one after the other, in the order they were received.
+	// The key is the message group id for SQS FIFO queues, the partition key for Kinesis and the item keys for
+	// DynamoDB Streams.
+	Ordered bool
+
+	// StopOnFirstFailure stops processing after an item fails and reports every item that was not processed as
+	// failed. With Ordered, only the items of the failing group are affected.
+	StopOnFirstFailure bool
+
+	// OnFailure is called with the failure identifier and error of every item whose processor failed, for example
+	// to log it. Items skipped because of StopOnFirstFailure are not reported. It may be called concurrently.
+	OnFailure func(ctx context.Context, id string, err error)
+}
+
+// item is a batch item along with its failure identifier and ordering key.
+type item[I any] struct {
+	value I
+	id    string
+	key   string
+}
+
+// process runs fn over the items following the configuration and returns the ids of the failed items, in the
+// order they were received.
+func process[I any](ctx context.Context, items []item[I], fn Processor[I], config Config) []string {
+	concurrency := max(config.Concurrency, 1)
+	groups := group(items, config.Ordered)
+	failed := make([]bool, len(items))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		stopped bool
+	)
+
+	isStopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return stopped || ctx.Err() != nil
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	for _, idx := range groups {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			for n, i := range idx {
+				if isStopped() {
+					markFailed(failed, idx[n:])
+					return
+				}
+
+				err := safeProcess(ctx, fn, items[i].value)
+				if err == nil {
+					continue
+				}
+
+				failed[i] = true
+
+				if config.OnFailure != nil {
+					config.OnFailure(ctx, items[i].id, err)
+				}
+
+				if !config.StopOnFirstFailure {
+					continue
+				}
+
+				markFailed(failed, idx[n+1:])
+
+				if !config.Ordered {
+					mu.Lock()
+					stopped = true
+					mu.Unlock()
+				}
+
+				return
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var ids []string
+
+	for i, f := range failed {
+		if f {
+			ids = append(ids, items[i].id)
+		}
+	}
+
+	return ids
+}
+
+// group splits the item indexes into groups whose items are processed one after the other. Without ordering every
+// item is its own group.
+func group[I any](items []item[I], ordered bool) [][]int {
+	if !ordered {
+		groups := make([][]int, len(items))
+		for i := range items {
+			groups[i] = []int{i}
+		}
+
+		return groups
+	}
+
+	var groups [][]int
+
+	pos := make(map[string]int)
+
+	for i, it := range items {
+		g, ok := pos[it.key]
+		if !ok {
+			g = len(groups)
+			pos[it.key] = g
+			groups = append(groups, nil)
+		}
+
+		groups[g] = append(groups[g], i)
+	}
+
+	return groups
+}
+
+func markFailed(failed []bool, idx []int) {
+	for _, i := range idx {
+		failed[i] = true
+	}
+}
+
+func safeProcess[I any](ctx context.Context, fn Processor[I], value I) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn(ctx, value)
+}
@@ -0,0 +1,221 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type order struct {
+	ID string `json:"id"`
+}
+
+func sqsMessage(id, body, group string) events.SQSMessage {
+	msg := events.SQSMessage{MessageId: id, Body: body, EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders"}
+
+	if group != "" {
+		msg.EventSourceARN += ".fifo"
+		msg.Attributes = map[string]string{"MessageGroupId": group}
+	}
+
+	return msg
+}
+
+func kinesisRecord(seq, key, data string) events.KinesisEventRecord {
+	return events.KinesisEventRecord{Kinesis: events.KinesisRecord{SequenceNumber: seq, PartitionKey: key, Data: []byte(data)}}
+}
+
+func failures[T any](items []T, id func(T) string) []string {
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, id(it))
+	}
+
+	return ids
+}
+
+func TestSQS(t *testing.T) {
+	t.Run("should report failed messages of standard queues", func(t *testing.T) {
+		var (
+			mu        sync.Mutex
+			onFailure []string
+		)
+
+		handler := SQS(JSONBody(func(_ context.Context, o order) error {
+			if o.ID == "2" {
+				return errors.New("boom")
+			}
+
+			return nil
+		}), Config{Concurrency: 4, OnFailure: func(_ context.Context, id string, _ error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			onFailure = append(onFailure, id)
+		}})
+
+		evt := events.SQSEvent{Records: []events.SQSMessage{
+			sqsMessage("m1", `{"id":"1"}`, ""),
+			sqsMessage("m2", `{"id":"2"}`, ""),
+			sqsMessage("m3", `not json`, ""),
+			sqsMessage("m4", `{"id":"4"}`, ""),
+		}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m2", "m3"}, failures(res.BatchItemFailures, func(f events.SQSBatchItemFailure) string {
+			return f.ItemIdentifier
+		}))
+		assert.ElementsMatch(t, []string{"m2", "m3"}, onFailure)
+	})
+
+	t.Run("should stop fifo message groups at first failure", func(t *testing.T) {
+		var processed sync.Map
+
+		handler := SQS(func(_ context.Context, msg events.SQSMessage) error {
+			processed.Store(msg.MessageId, true)
+
+			if msg.MessageId == "a2" {
+				return errors.New("boom")
+			}
+
+			return nil
+		}, DefaultSQSConfig())
+
+		evt := events.SQSEvent{Records: []events.SQSMessage{
+			sqsMessage("a1", "", "a"),
+			sqsMessage("b1", "", "b"),
+			sqsMessage("a2", "", "a"),
+			sqsMessage("a3", "", "a"),
+			sqsMessage("b2", "", "b"),
+		}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a2", "a3"}, failures(res.BatchItemFailures, func(f events.SQSBatchItemFailure) string {
+			return f.ItemIdentifier
+		}))
+
+		_, ok := processed.Load("a3")
+		assert.False(t, ok)
+
+		_, ok = processed.Load("b2")
+		assert.True(t, ok)
+	})
+
+	t.Run("should report panics as failures", func(t *testing.T) {
+		handler := SQS(func(context.Context, events.SQSMessage) error {
+			panic("boom")
+		}, DefaultSQSConfig())
+
+		evt := events.SQSEvent{Records: []events.SQSMessage{sqsMessage("m1", "", "")}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Len(t, res.BatchItemFailures, 1)
+	})
+
+	t.Run("should limit concurrency", func(t *testing.T) {
+		var inFlight, peak atomic.Int32
+
+		handler := SQS(func(context.Context, events.SQSMessage) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			return nil
+		}, Config{Concurrency: 2})
+
+		evt := events.SQSEvent{}
+		for range 8 {
+			evt.Records = append(evt.Records, sqsMessage("m", "", ""))
+		}
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), peak.Load())
+	})
+}
+
+func TestKinesis(t *testing.T) {
+	t.Run("should stop at first failure and report the rest", func(t *testing.T) {
+		calls := 0
+
+		handler := Kinesis(JSONData(func(_ context.Context, o order) error {
+			calls++
+
+			if o.ID == "2" {
+				return errors.New("boom")
+			}
+
+			return nil
+		}), DefaultStreamConfig())
+
+		evt := events.KinesisEvent{Records: []events.KinesisEventRecord{
+			kinesisRecord("100", "k1", `{"id":"1"}`),
+			kinesisRecord("101", "k2", `{"id":"2"}`),
+			kinesisRecord("102", "k1", `{"id":"3"}`),
+		}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, []string{"101", "102"}, failures(res.BatchItemFailures, func(f events.KinesisBatchItemFailure) string {
+			return f.ItemIdentifier
+		}))
+	})
+}
+
+func TestDynamoDB(t *testing.T) {
+	t.Run("should keep processing other keys when ordered", func(t *testing.T) {
+		record := func(seq, id string) events.DynamoDBEventRecord {
+			return events.DynamoDBEventRecord{Change: events.DynamoDBStreamRecord{
+				SequenceNumber: seq,
+				Keys:           map[string]events.DynamoDBAttributeValue{"pk": events.NewStringAttribute(id)},
+			}}
+		}
+
+		handler := DynamoDB(func(_ context.Context, rec events.DynamoDBEventRecord) error {
+			if rec.Change.SequenceNumber == "1" {
+				return errors.New("boom")
+			}
+
+			return nil
+		}, Config{Ordered: true, StopOnFirstFailure: true})
+
+		evt := events.DynamoDBEvent{Records: []events.DynamoDBEventRecord{
+			record("1", "a"),
+			record("2", "b"),
+			record("3", "a"),
+		}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "3"}, failures(res.BatchItemFailures, func(f events.DynamoDBBatchItemFailure) string {
+			return f.ItemIdentifier
+		}))
+	})
+}
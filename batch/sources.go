@@ -0,0 +1,123 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/Drafteame/engine"
+)
+
+// DefaultSQSConfig returns the default configuration for SQS queues, which processes messages one at a time and
+// independently of each other. FIFO queues are always processed in message group order and stop on failure.
+func DefaultSQSConfig() Config {
+	return Config{Concurrency: 1}
+}
+
+// DefaultStreamConfig returns the default configuration for Kinesis and DynamoDB streams, which processes records
+// in order and stops at the first failure so the stream checkpoint is kept.
+func DefaultStreamConfig() Config {
+	return Config{Concurrency: 1, StopOnFirstFailure: true}
+}
+
+// SQS creates an SQS handler that reports failed messages as batch item failures. The event source mapping must
+// enable ReportBatchItemFailures.
+func SQS(fn Processor[events.SQSMessage], config Config) engine.Handler[events.SQSEvent, events.SQSEventResponse] {
+	return func(ctx context.Context, evt events.SQSEvent) (events.SQSEventResponse, error) {
+		cfg := config
+		items := make([]item[events.SQSMessage], len(evt.Records))
+
+		for i, msg := range evt.Records {
+			items[i] = item[events.SQSMessage]{value: msg, id: msg.MessageId, key: msg.Attributes["MessageGroupId"]}
+
+			if strings.HasSuffix(msg.EventSourceARN, ".fifo") {
+				cfg.Ordered = true
+				cfg.StopOnFirstFailure = true
+			}
+		}
+
+		var res events.SQSEventResponse
+
+		for _, id := range process(ctx, items, fn, cfg) {
+			res.BatchItemFailures = append(res.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: id})
+		}
+
+		return res, nil
+	}
+}
+
+// Kinesis creates a Kinesis handler that reports failed records as batch item failures, identified by their
+// sequence number. The event source mapping must enable ReportBatchItemFailures.
+func Kinesis(fn Processor[events.KinesisEventRecord], config Config) engine.Handler[events.KinesisEvent, events.KinesisEventResponse] {
+	return func(ctx context.Context, evt events.KinesisEvent) (events.KinesisEventResponse, error) {
+		items := make([]item[events.KinesisEventRecord], len(evt.Records))
+
+		for i, rec := range evt.Records {
+			items[i] = item[events.KinesisEventRecord]{
+				value: rec,
+				id:    rec.Kinesis.SequenceNumber,
+				key:   rec.Kinesis.PartitionKey,
+			}
+		}
+
+		var res events.KinesisEventResponse
+
+		for _, id := range process(ctx, items, fn, config) {
+			res.BatchItemFailures = append(res.BatchItemFailures, events.KinesisBatchItemFailure{ItemIdentifier: id})
+		}
+
+		return res, nil
+	}
+}
+
+// DynamoDB creates a DynamoDB Streams handler that reports failed records as batch item failures, identified by
+// their sequence number. The event source mapping must enable ReportBatchItemFailures.
+func DynamoDB(fn Processor[events.DynamoDBEventRecord], config Config) engine.Handler[events.DynamoDBEvent, events.DynamoDBEventResponse] {
+	return func(ctx context.Context, evt events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+		items := make([]item[events.DynamoDBEventRecord], len(evt.Records))
+
+		for i, rec := range evt.Records {
+			key, _ := json.Marshal(rec.Change.Keys)
+
+			items[i] = item[events.DynamoDBEventRecord]{
+				value: rec,
+				id:    rec.Change.SequenceNumber,
+				key:   string(key),
+			}
+		}
+
+		var res events.DynamoDBEventResponse
+
+		for _, id := range process(ctx, items, fn, config) {
+			res.BatchItemFailures = append(res.BatchItemFailures, events.DynamoDBBatchItemFailure{ItemIdentifier: id})
+		}
+
+		return res, nil
+	}
+}
+
+// JSONBody adapts a processor of "T" to SQS messages whose body is the JSON encoding of "T".
+func JSONBody[T any](fn Processor[T]) Processor[events.SQSMessage] {
+	return func(ctx context.Context, msg events.SQSMessage) error {
+		var v T
+		if err := json.Unmarshal([]byte(msg.Body), &v); err != nil {
+			return err
+		}
+
+		return fn(ctx, v)
+	}
+}
+
+// JSONData adapts a processor of "T" to Kinesis records whose data is the JSON encoding of "T".
+func JSONData[T any](fn Processor[T]) Processor[events.KinesisEventRecord] {
+	return func(ctx context.Context, rec events.KinesisEventRecord) error {
+		var v T
+		if err := json.Unmarshal(rec.Kinesis.Data, &v); err != nil {
+			return err
+		}
+
+		return fn(ctx, v)
+	}
+}
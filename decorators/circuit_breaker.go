@@ -0,0 +1,256 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Drafteame/engine"
+)
+
+// ErrCircuitOpen is returned, or passed to the fallback, for invocations rejected by an open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every invocation through while counting failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every invocation until the open timeout elapses.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial invocations through to decide whether to close again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig is the configuration for the CircuitBreaker decorator.
+type CircuitBreakerConfig[T, R any] struct {
+	// ConsecutiveFailures opens the circuit after that many failures in a row. Zero disables the threshold.
+	ConsecutiveFailures int
+
+	// FailureRate opens the circuit when the fraction of failed invocations in the current window reaches it. Zero
+	// disables the threshold.
+	FailureRate float64
+
+	// MinRequests is the number of invocations the current window needs before FailureRate is evaluated.
+	MinRequests int
+
+	// Window is the period after which the failure rate counters are reset.
+	Window time.Duration
+
+	// OpenTimeout is how long the circuit stays open before letting trial invocations through.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of trial invocations allowed while half-open. The circuit closes once all of
+	// them succeed and opens again at the first failure.
+	HalfOpenRequests int
+
+	// IsFailure tells whether the error of an invocation counts as a failure. Defaults to any non nil error.
+	IsFailure func(error) bool
+
+	// Fallback handles the invocations rejected while the circuit is open, receiving ErrCircuitOpen. Without it
+	// those invocations return ErrCircuitOpen.
+	Fallback func(context.Context, T, error) (R, error)
+
+	// OnStateChange is called on every state transition, for example to log or emit metrics. It runs while the
+	// breaker is locked, so it must not call the decorated handler.
+	OnStateChange func(from, to CircuitState)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// DefaultCircuitBreakerConfig returns the default configuration for the CircuitBreaker decorator.
+func DefaultCircuitBreakerConfig[T, R any]() CircuitBreakerConfig[T, R] {
+	return CircuitBreakerConfig[T, R]{
+		ConsecutiveFailures: 5,
+		FailureRate:         0.5,
+		MinRequests:         10,
+		Window:              time.Minute,
+		OpenTimeout:         30 * time.Second,
+		HalfOpenRequests:    1,
+	}
+}
+
+// CircuitBreaker is a decorator that stops calling the handler after repeated failures, protecting a failing
+// downstream dependency. Its state lives in memory for the lifetime of the execution environment.
+func CircuitBreaker[T, R any]() engine.Decorator[T, R] {
+	return CircuitBreakerWithConfig(DefaultCircuitBreakerConfig[T, R]())
+}
+
+// CircuitBreakerWithConfig is a decorator that stops calling the handler after repeated failures with a custom
+// configuration.
+func CircuitBreakerWithConfig[T, R any](config CircuitBreakerConfig[T, R]) engine.Decorator[T, R] {
+	cb := &circuitBreaker[T, R]{config: config}
+
+	if cb.config.IsFailure == nil {
+		cb.config.IsFailure = func(err error) bool { return err != nil }
+	}
+
+	if cb.config.Now == nil {
+		cb.config.Now = time.Now
+	}
+
+	cb.config.HalfOpenRequests = max(cb.config.HalfOpenRequests, 1)
+	cb.windowStart = cb.config.Now()
+
+	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
+		return func(ctx context.Context, evt T) (res R, err error) {
+			generation, ok := cb.allow()
+			if !ok {
+				if cb.config.Fallback != nil {
+					return cb.config.Fallback(ctx, evt, ErrCircuitOpen)
+				}
+
+				return res, ErrCircuitOpen
+			}
+
+			completed := false
+
+			defer func() {
+				// a panicking handler counts as a failure
+				cb.record(generation, !completed || cb.config.IsFailure(err))
+			}()
+
+			res, err = handler(ctx, evt)
+			completed = true
+
+			return res, err
+		}
+	}
+}
+
+type circuitBreaker[T, R any] struct {
+	config CircuitBreakerConfig[T, R]
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64
+	openedAt    time.Time
+	windowStart time.Time
+	requests    int
+	failures    int
+	consecutive int
+	trials      int
+	successes   int
+}
+
+// allow reports whether an invocation may call the handler, returning the generation it belongs to.
+func (cb *circuitBreaker[T, R]) allow() (uint64, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.config.Now()
+
+	switch cb.state {
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) < cb.config.OpenTimeout {
+			return 0, false
+		}
+
+		cb.transition(CircuitHalfOpen, now)
+	case CircuitClosed:
+		if cb.config.Window > 0 && now.Sub(cb.windowStart) >= cb.config.Window {
+			cb.resetCounts(now)
+		}
+	}
+
+	if cb.state == CircuitHalfOpen {
+		if cb.trials >= cb.config.HalfOpenRequests {
+			return 0, false
+		}
+
+		cb.trials++
+	}
+
+	return cb.generation, true
+}
+
+// record accounts the outcome of an invocation, ignoring those started before the last state transition.
+func (cb *circuitBreaker[T, R]) record(generation uint64, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+
+	now := cb.config.Now()
+
+	if cb.state == CircuitHalfOpen {
+		if failed {
+			cb.transition(CircuitOpen, now)
+			return
+		}
+
+		cb.successes++
+		if cb.successes >= cb.config.HalfOpenRequests {
+			cb.transition(CircuitClosed, now)
+		}
+
+		return
+	}
+
+	cb.requests++
+
+	if !failed {
+		cb.consecutive = 0
+		return
+	}
+
+	cb.failures++
+	cb.consecutive++
+
+	if cb.tripped() {
+		cb.transition(CircuitOpen, now)
+	}
+}
+
+func (cb *circuitBreaker[T, R]) tripped() bool {
+	if cb.config.ConsecutiveFailures > 0 && cb.consecutive >= cb.config.ConsecutiveFailures {
+		return true
+	}
+
+	return cb.config.FailureRate > 0 &&
+		cb.requests >= cb.config.MinRequests &&
+		float64(cb.failures)/float64(cb.requests) >= cb.config.FailureRate
+}
+
+func (cb *circuitBreaker[T, R]) transition(to CircuitState, now time.Time) {
+	from := cb.state
+
+	cb.state = to
+	cb.generation++
+	cb.trials = 0
+	cb.successes = 0
+	cb.resetCounts(now)
+
+	if to == CircuitOpen {
+		cb.openedAt = now
+	}
+
+	if cb.config.OnStateChange != nil {
+		cb.config.OnStateChange(from, to)
+	}
+}
+
+func (cb *circuitBreaker[T, R]) resetCounts(now time.Time) {
+	cb.windowStart = now
+	cb.requests = 0
+	cb.failures = 0
+	cb.consecutive = 0
+}
@@ -0,0 +1,151 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Drafteame/engine"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestCircuitBreaker(t *testing.T) {
+	errDownstream := errors.New("downstream unavailable")
+
+	newHandler := func(calls *int, fail *bool) engine.Handler[string, string] {
+		return func(_ context.Context, evt string) (string, error) {
+			*calls++
+
+			if *fail {
+				return "", errDownstream
+			}
+
+			return evt, nil
+		}
+	}
+
+	t.Run("should open after consecutive failures and recover through half-open", func(t *testing.T) {
+		var (
+			calls       int
+			fail        = true
+			transitions []string
+			clock       = &fakeClock{now: time.Now()}
+		)
+
+		config := DefaultCircuitBreakerConfig[string, string]()
+		config.ConsecutiveFailures = 3
+		config.FailureRate = 0
+		config.Now = clock.Now
+		config.OnStateChange = func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		}
+
+		handler := CircuitBreakerWithConfig(config)(newHandler(&calls, &fail))
+
+		for range 3 {
+			_, err := handler(context.Background(), "hello")
+			assert.ErrorIs(t, err, errDownstream)
+		}
+
+		_, err := handler(context.Background(), "hello")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 3, calls)
+
+		clock.now = clock.now.Add(config.OpenTimeout)
+
+		_, err = handler(context.Background(), "hello")
+		assert.ErrorIs(t, err, errDownstream)
+
+		_, err = handler(context.Background(), "hello")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+
+		clock.now = clock.now.Add(config.OpenTimeout)
+		fail = false
+
+		res, err := handler(context.Background(), "hello")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", res)
+		assert.Equal(t, 5, calls)
+
+		assert.Equal(t, []string{
+			"closed->open",
+			"open->half-open",
+			"half-open->open",
+			"open->half-open",
+			"half-open->closed",
+		}, transitions)
+	})
+
+	t.Run("should open when the failure rate is reached", func(t *testing.T) {
+		var (
+			calls int
+			fail  bool
+		)
+
+		config := DefaultCircuitBreakerConfig[string, string]()
+		config.ConsecutiveFailures = 0
+		config.FailureRate = 0.5
+		config.MinRequests = 4
+
+		handler := CircuitBreakerWithConfig(config)(newHandler(&calls, &fail))
+
+		for i := range 4 {
+			fail = i%2 == 1
+			_, _ = handler(context.Background(), "hello")
+		}
+
+		_, err := handler(context.Background(), "hello")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("should use the fallback while open", func(t *testing.T) {
+		var (
+			calls int
+			fail  = true
+		)
+
+		config := DefaultCircuitBreakerConfig[string, string]()
+		config.ConsecutiveFailures = 1
+		config.Fallback = func(_ context.Context, evt string, err error) (string, error) {
+			assert.ErrorIs(t, err, ErrCircuitOpen)
+			return "cached " + evt, nil
+		}
+
+		handler := CircuitBreakerWithConfig(config)(newHandler(&calls, &fail))
+
+		_, _ = handler(context.Background(), "hello")
+		res, err := handler(context.Background(), "hello")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cached hello", res)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should ignore errors that are not failures", func(t *testing.T) {
+		var (
+			calls int
+			fail  = true
+		)
+
+		config := DefaultCircuitBreakerConfig[string, string]()
+		config.ConsecutiveFailures = 1
+		config.IsFailure = func(err error) bool { return !errors.Is(err, errDownstream) }
+
+		handler := CircuitBreakerWithConfig(config)(newHandler(&calls, &fail))
+
+		_, _ = handler(context.Background(), "hello")
+		_, err := handler(context.Background(), "hello")
+
+		assert.ErrorIs(t, err, errDownstream)
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,112 @@
+package decorators
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultLogEventDebugEnv is the environment variable checked by the default LogEvent configuration to force debug
+// logs.
+const DefaultLogEventDebugEnv = "LOG_EVENT_DEBUG"
+
+// DebugEnv returns a debug switch enabled while the named environment variable holds a true boolean value. It is read
+// on every invocation.
+func DebugEnv[T any](name string) func(context.Context, T) bool {
+	return func(context.Context, T) bool {
+		enabled, _ := strconv.ParseBool(os.Getenv(name))
+		return enabled
+	}
+}
+
+// DebugHeader returns a debug switch enabled when the event carries the named header, matched case insensitively in
+// its "headers" or "multiValueHeaders" object, with a true boolean value. It suits API gateway events.
+func DebugHeader[T any](name string) func(context.Context, T) bool {
+	return func(_ context.Context, evt T) bool {
+		doc, ok := decodeDebugEvent(evt)
+		if !ok {
+			return false
+		}
+
+		for _, key := range []string{"headers", "multiValueHeaders"} {
+			headers, _ := doc[key].(map[string]any)
+
+			for k, v := range headers {
+				if strings.EqualFold(k, name) && isTrue(v) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// DebugField returns a debug switch enabled when the dot separated JSON path of the event holds a true boolean value,
+// either as a JSON boolean or a string.
+func DebugField[T any](path string) func(context.Context, T) bool {
+	segments := strings.Split(path, ".")
+
+	return func(_ context.Context, evt T) bool {
+		doc, ok := decodeDebugEvent(evt)
+		if !ok {
+			return false
+		}
+
+		var v any = doc
+
+		for _, s := range segments {
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return false
+			}
+
+			v = obj[s]
+		}
+
+		return isTrue(v)
+	}
+}
+
+// DebugAny returns a debug switch enabled when any of the given switches is.
+func DebugAny[T any](switches ...func(context.Context, T) bool) func(context.Context, T) bool {
+	return func(ctx context.Context, evt T) bool {
+		for _, fn := range switches {
+			if fn != nil && fn(ctx, evt) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func decodeDebugEvent(evt any) (map[string]any, bool) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return nil, false
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false
+	}
+
+	return doc, true
+}
+
+func isTrue(v any) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		enabled, _ := strconv.ParseBool(val)
+		return enabled
+	case []any:
+		return len(val) > 0 && isTrue(val[0])
+	default:
+		return false
+	}
+}
@@ -3,16 +3,31 @@ package decorators
 import (
 	"context"
 	"log/slog"
+	"math/rand/v2"
 
 	"github.com/Drafteame/engine"
+	"github.com/Drafteame/engine/logger"
+	"github.com/Drafteame/engine/redact"
 )
 
 type LogEventConfig[T, R any] struct {
 	LogFunc func(context.Context, T, R, error)
+
+	// Redact is applied to the event, response and error message before they reach LogFunc. The zero value logs
+	// everything as is.
+	Redact redact.Config
+
+	// SampleRate is the fraction, between 0 and 1, of successful invocations that are logged. Failed and debug
+	// invocations are always logged. Zero or values of 1 and above log every invocation.
+	SampleRate float64
+
+	// Debug reports whether the invocation asked for debug logs. Debug invocations are always logged, and the logger
+	// returned by logger.From for them emits debug records.
+	Debug func(context.Context, T) bool
 }
 
 func DefaultLogEventLogFunc[T, R any](ctx context.Context, evt T, res R, err error) {
-	log := slog.Default()
+	log := logger.From(ctx)
 
 	if err != nil {
 		log.ErrorContext(ctx, "error occurred", "error", err, "event", evt, "response", res)
@@ -23,26 +38,65 @@ func DefaultLogEventLogFunc[T, R any](ctx context.Context, evt T, res R, err err
 }
 
 func DefaultLogEventConfig[T, R any]() LogEventConfig[T, R] {
-	return LogEventConfig[T, R]{LogFunc: DefaultLogEventLogFunc[T, R]}
+	return LogEventConfig[T, R]{
+		LogFunc: DefaultLogEventLogFunc[T, R],
+		Redact:  DefaultRedaction[T, R](),
+		Debug:   DebugEnv[T](DefaultLogEventDebugEnv),
+	}
+}
+
+// DefaultRedaction returns the redaction presets of the event and response types when they implement redact.Preset,
+// or redact.Default() otherwise.
+func DefaultRedaction[T, R any]() redact.Config {
+	var (
+		evt T
+		res R
+	)
+
+	config := redact.Default()
+
+	if p, ok := any(evt).(redact.Preset); ok {
+		config = config.Merge(p.Redaction())
+	}
+
+	if p, ok := any(res).(redact.Preset); ok {
+		config = config.Merge(p.Redaction())
+	}
+
+	return config
 }
 
 func LogEventWithConfig[T, R any](config LogEventConfig[T, R]) engine.Decorator[T, R] {
 	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
 		return func(ctx context.Context, evt T) (R, error) {
+			debug := config.Debug != nil && config.Debug(ctx, evt)
+			if debug {
+				ctx = logger.WithLevel(ctx, slog.LevelDebug)
+			}
+
 			res, err := handler(ctx, evt)
 
+			if err == nil && !debug && !sampled(config.SampleRate) {
+				return res, err
+			}
+
 			logFunc := DefaultLogEventLogFunc[T, R]
 			if config.LogFunc != nil {
 				logFunc = config.LogFunc
 			}
 
-			logFunc(ctx, evt, res, err)
+			logFunc(ctx, redact.Value(evt, config.Redact), redact.Value(res, config.Redact), redact.Error(err, config.Redact))
 
 			return res, err
 		}
 	}
 }
 
+// sampled reports whether a successful invocation is logged with the given sample rate.
+func sampled(rate float64) bool {
+	return rate <= 0 || rate >= 1 || rand.Float64() < rate
+}
+
 func LogEvent[T, R any]() engine.Decorator[T, R] {
 	return LogEventWithConfig(DefaultLogEventConfig[T, R]())
 }
@@ -0,0 +1,143 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Drafteame/engine/handlers/apigatewayv2"
+	"github.com/Drafteame/engine/logger"
+	"github.com/Drafteame/engine/redact"
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+func TestLogEvent(t *testing.T) {
+	t.Run("should redact api gateway events by default", func(t *testing.T) {
+		var logged apigatewayv2.HTTPRequest
+
+		config := DefaultLogEventConfig[apigatewayv2.HTTPRequest, apigatewayv2.HTTPResponse]()
+		config.LogFunc = func(_ context.Context, evt apigatewayv2.HTTPRequest, _ apigatewayv2.HTTPResponse, _ error) {
+			logged = evt
+		}
+
+		handler := func(_ context.Context, evt apigatewayv2.HTTPRequest) (apigatewayv2.HTTPResponse, error) {
+			assert.Equal(t, "Bearer abc", evt.Headers["authorization"])
+			return apigatewayv2.HTTPResponse{StatusCode: 200}, nil
+		}
+
+		evt := apigatewayv2.HTTPRequest{
+			RawPath: "/users",
+			Headers: map[string]string{"authorization": "Bearer abc", "accept": "*/*"},
+			Cookies: []string{"session=1"},
+			Body:    `{"email":"jane@example.com"}`,
+		}
+
+		_, err := testengine.New(context.Background(), evt, handler).
+			Use(LogEventWithConfig(config)).
+			Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/users", logged.RawPath)
+		assert.Equal(t, redact.DefaultReplacement, logged.Headers["authorization"])
+		assert.Equal(t, "*/*", logged.Headers["accept"])
+		assert.Equal(t, []string{redact.DefaultReplacement}, logged.Cookies)
+		assert.Equal(t, redact.DefaultReplacement, logged.Body)
+	})
+
+	t.Run("should redact logged errors and return the original", func(t *testing.T) {
+		var logged error
+
+		cause := errors.New("user jane@example.com not found")
+
+		handler := func(context.Context, string) (string, error) {
+			return "", cause
+		}
+
+		_, err := testengine.New(context.Background(), "hello", handler).
+			Use(LogEventWithConfig(LogEventConfig[string, string]{
+				LogFunc: func(_ context.Context, _ string, _ string, err error) {
+					logged = err
+				},
+				Redact: redact.Config{Patterns: []*regexp.Regexp{regexp.MustCompile(`\S+@\S+`)}},
+			})).
+			Run()
+
+		assert.Same(t, cause, err)
+		assert.Equal(t, "user [REDACTED] not found", logged.Error())
+		assert.ErrorIs(t, logged, cause)
+	})
+
+	t.Run("should only log sampled successful invocations", func(t *testing.T) {
+		logged := 0
+
+		config := LogEventConfig[string, string]{
+			LogFunc:    func(context.Context, string, string, error) { logged++ },
+			SampleRate: 1e-12,
+		}
+
+		handler := func(_ context.Context, evt string) (string, error) {
+			if evt == "fail" {
+				return "", errors.New("boom")
+			}
+
+			return evt, nil
+		}
+
+		for range 10 {
+			_, _ = testengine.New(context.Background(), "ok", handler).Use(LogEventWithConfig(config)).Run()
+		}
+
+		assert.Equal(t, 0, logged)
+
+		_, _ = testengine.New(context.Background(), "fail", handler).Use(LogEventWithConfig(config)).Run()
+
+		assert.Equal(t, 1, logged)
+	})
+
+	t.Run("should escalate debug invocations", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logged := 0
+
+		config := LogEventConfig[apigatewayv2.HTTPRequest, apigatewayv2.HTTPResponse]{
+			LogFunc:    func(context.Context, apigatewayv2.HTTPRequest, apigatewayv2.HTTPResponse, error) { logged++ },
+			SampleRate: 1e-12,
+			Debug: DebugAny(
+				DebugHeader[apigatewayv2.HTTPRequest]("X-Debug"),
+				DebugField[apigatewayv2.HTTPRequest]("queryStringParameters.debug"),
+			),
+		}
+
+		handler := func(ctx context.Context, _ apigatewayv2.HTTPRequest) (apigatewayv2.HTTPResponse, error) {
+			logger.From(ctx).Debug("details")
+			return apigatewayv2.HTTPResponse{}, nil
+		}
+
+		ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+		events := []apigatewayv2.HTTPRequest{
+			{Headers: map[string]string{"x-debug": "true"}},
+			{QueryStringParameters: map[string]string{"debug": "1"}},
+			{Headers: map[string]string{"x-debug": "false"}},
+		}
+
+		for _, evt := range events {
+			_, err := testengine.New(ctx, evt, handler).Use(LogEventWithConfig(config)).Run()
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, logged)
+		assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("msg=details")))
+	})
+
+	t.Run("should escalate every invocation with the debug environment variable", func(t *testing.T) {
+		t.Setenv(DefaultLogEventDebugEnv, "true")
+
+		assert.True(t, DefaultLogEventConfig[string, string]().Debug(context.Background(), "hello"))
+	})
+}
@@ -0,0 +1,84 @@
+package decorators
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+
+	"github.com/Drafteame/engine"
+	"github.com/Drafteame/engine/logger"
+)
+
+// traceIDKey is the context key used by the aws-lambda-go runtime to store the X-Ray trace header.
+const traceIDKey = "x-amzn-trace-id"
+
+// LoggerConfig is the configuration for the Logger decorator.
+type LoggerConfig struct {
+	// Logger is the base logger tagged with the invocation fields. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// DefaultLoggerConfig returns the default configuration for the Logger decorator.
+func DefaultLoggerConfig() LoggerConfig {
+	return LoggerConfig{Logger: nil}
+}
+
+// Logger is a decorator that stores a request scoped logger in the context, tagged with the AWS request id, function
+// name and version, cold start flag and trace id. Handlers retrieve it with logger.From.
+func Logger[T, R any]() engine.Decorator[T, R] {
+	return LoggerWithConfig[T, R](DefaultLoggerConfig())
+}
+
+// LoggerWithConfig is a decorator that stores a request scoped logger in the context with a custom configuration.
+func LoggerWithConfig[T, R any](config LoggerConfig) engine.Decorator[T, R] {
+	var warm atomic.Bool
+
+	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
+		return func(ctx context.Context, evt T) (R, error) {
+			base := config.Logger
+			if base == nil {
+				base = slog.Default()
+			}
+
+			args := []any{"cold_start", !warm.Swap(true)}
+
+			if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+				args = append(args, "request_id", lc.AwsRequestID)
+			}
+
+			if lambdacontext.FunctionName != "" {
+				args = append(args, "function_name", lambdacontext.FunctionName)
+			}
+
+			if lambdacontext.FunctionVersion != "" {
+				args = append(args, "function_version", lambdacontext.FunctionVersion)
+			}
+
+			if traceID := traceID(ctx); traceID != "" {
+				args = append(args, "trace_id", traceID)
+			}
+
+			return handler(logger.NewContext(ctx, base.With(args...)), evt)
+		}
+	}
+}
+
+// traceID returns the root id of the X-Ray trace header of the invocation.
+func traceID(ctx context.Context) string {
+	header, _ := ctx.Value(traceIDKey).(string)
+	if header == "" {
+		header = os.Getenv("_X_AMZN_TRACE_ID")
+	}
+
+	for _, part := range strings.Split(header, ";") {
+		if root, ok := strings.CutPrefix(part, "Root="); ok {
+			return root
+		}
+	}
+
+	return header
+}
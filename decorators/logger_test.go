@@ -0,0 +1,53 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Drafteame/engine/logger"
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("should inject a logger tagged with the invocation fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		handler := func(ctx context.Context, _ string) (string, error) {
+			logger.From(ctx).Info("processing")
+			return "ok", nil
+		}
+
+		ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+		ctx = context.WithValue(ctx, traceIDKey, "Root=1-abc-def;Parent=123;Sampled=1")
+
+		_, err := testengine.New(ctx, "hello", handler).
+			Use(LoggerWithConfig[string, string](LoggerConfig{Logger: slog.New(slog.NewTextHandler(&buf, nil))})).
+			Run()
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "msg=processing cold_start=true request_id=req-1 trace_id=1-abc-def")
+	})
+
+	t.Run("should only flag the first invocation as cold start", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		handler := func(ctx context.Context, _ string) (string, error) {
+			logger.From(ctx).Info("processing")
+			return "ok", nil
+		}
+
+		decorator := LoggerWithConfig[string, string](LoggerConfig{Logger: slog.New(slog.NewTextHandler(&buf, nil))})
+		decorated := decorator(handler)
+
+		_, _ = decorated(context.Background(), "one")
+		_, _ = decorated(context.Background(), "two")
+
+		assert.Contains(t, buf.String(), "cold_start=true")
+		assert.Contains(t, buf.String(), "cold_start=false")
+	})
+}
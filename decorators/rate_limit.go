@@ -0,0 +1,294 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Drafteame/engine"
+)
+
+// ErrRateLimited is matched by the errors returned for invocations rejected by the RateLimit decorator.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError is returned for invocations rejected by the RateLimit decorator.
+type RateLimitError struct {
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s for %q, retry after %s", ErrRateLimited, e.Key, e.RetryAfter)
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimitStore holds the token buckets of the RateLimit decorator. Implementations backed by a shared store, such as
+// Redis or DynamoDB, enforce the limits across execution environments.
+type RateLimitStore interface {
+	// Take removes a token from the bucket of key, refilled with rate tokens per second up to burst tokens. It
+	// reports whether a token was available and, when it was not, how long until the next one is.
+	Take(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// RateLimitConfig is the configuration for the RateLimit decorator.
+type RateLimitConfig[T, R any] struct {
+	// Rate is the number of invocations per second allowed for each key.
+	Rate float64
+
+	// Burst is the number of invocations allowed at once for each key. Defaults to 1.
+	Burst int
+
+	// Key returns the key whose limit applies to the event, such as a tenant id. Defaults to a single limit shared by
+	// every invocation.
+	Key func(context.Context, T) string
+
+	// Store holds the token buckets. Defaults to a store local to the execution environment.
+	Store RateLimitStore
+
+	// Wait makes limited invocations wait for a token, up to the context deadline, instead of being rejected.
+	Wait bool
+
+	// OnLimit handles the rejected invocations, receiving a *RateLimitError. Without it they return that error.
+	OnLimit func(context.Context, T, error) (R, error)
+}
+
+// RateLimit is a decorator that limits the invocations of the handler to rate per second with the given burst.
+func RateLimit[T, R any](rate float64, burst int) engine.Decorator[T, R] {
+	return RateLimitWithConfig(RateLimitConfig[T, R]{Rate: rate, Burst: burst})
+}
+
+// RateLimitWithConfig is a decorator that limits the invocations of the handler with a custom configuration.
+func RateLimitWithConfig[T, R any](config RateLimitConfig[T, R]) engine.Decorator[T, R] {
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore()
+	}
+
+	config.Burst = max(config.Burst, 1)
+
+	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
+		return func(ctx context.Context, evt T) (R, error) {
+			var key string
+			if config.Key != nil {
+				key = config.Key(ctx, evt)
+			}
+
+			err := takeToken(ctx, config, key)
+			if err == nil {
+				return handler(ctx, evt)
+			}
+
+			if config.OnLimit != nil && errors.Is(err, ErrRateLimited) {
+				return config.OnLimit(ctx, evt, err)
+			}
+
+			var zero R
+
+			return zero, err
+		}
+	}
+}
+
+func takeToken[T, R any](ctx context.Context, config RateLimitConfig[T, R], key string) error {
+	for {
+		ok, retryAfter, err := config.Store.Take(ctx, key, config.Rate, config.Burst)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		limitErr := &RateLimitError{Key: key, RetryAfter: retryAfter}
+
+		if !config.Wait {
+			return limitErr
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < retryAfter {
+			return limitErr
+		}
+
+		timer := time.NewTimer(retryAfter)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(limitErr, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// memoryStoreSweepInterval is how often MemoryRateLimitStore drops the buckets that refilled.
+const memoryStoreSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  int
+}
+
+// full reports whether the bucket refilled by now, which makes it equal to a new bucket.
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= float64(b.burst)
+}
+
+// MemoryRateLimitStore is a RateLimitStore local to the execution environment. Buckets that refilled are dropped
+// periodically, so keys seen once do not accumulate.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+// NewMemoryRateLimitStore returns an empty MemoryRateLimitStore.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	b.rate = rate
+	b.burst = burst
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	if rate <= 0 {
+		return false, time.Duration(math.MaxInt64), nil
+	}
+
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second)), nil
+}
+
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < memoryStoreSweepInterval {
+		return
+	}
+
+	s.swept = now
+
+	for key, b := range s.buckets {
+		if b.full(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// ConcurrencyLimiter limits how many goroutines run at once against a downstream, optionally per key. It is meant to
+// bound the fan-out of a handler within an execution environment.
+type ConcurrencyLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	slots map[string]*concurrencySlots
+}
+
+// concurrencySlots are the slots of a key, dropped once no goroutine holds or waits for them.
+type concurrencySlots struct {
+	ch   chan struct{}
+	refs int
+}
+
+// NewConcurrencyLimiter returns a limiter allowing limit concurrent calls per key.
+func NewConcurrencyLimiter(limit int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{limit: max(limit, 1), slots: make(map[string]*concurrencySlots)}
+}
+
+// Acquire blocks until a slot for key is free or ctx is done. The returned function releases the slot.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+
+	slots, ok := l.slots[key]
+	if !ok {
+		slots = &concurrencySlots{ch: make(chan struct{}, l.limit)}
+		l.slots[key] = slots
+	}
+
+	slots.refs++
+
+	l.mu.Unlock()
+
+	select {
+	case slots.ch <- struct{}{}:
+		return func() {
+			<-slots.ch
+			l.unref(key, slots)
+		}, nil
+	case <-ctx.Done():
+		l.unref(key, slots)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *ConcurrencyLimiter) unref(key string, slots *concurrencySlots) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots.refs--
+
+	if slots.refs == 0 {
+		delete(l.slots, key)
+	}
+}
+
+// Do calls fn once a slot for key is free.
+func (l *ConcurrencyLimiter) Do(ctx context.Context, key string, fn func(context.Context) error) error {
+	release, err := l.Acquire(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return fn(ctx)
+}
+
+// ConcurrencyLimit is a decorator that limits the concurrent invocations of the handler with the given limiter, keyed
+// by the optional key function.
+func ConcurrencyLimit[T, R any](limiter *ConcurrencyLimiter, key func(context.Context, T) string) engine.Decorator[T, R] {
+	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
+		return func(ctx context.Context, evt T) (R, error) {
+			var k string
+			if key != nil {
+				k = key(ctx, evt)
+			}
+
+			release, err := limiter.Acquire(ctx, k)
+			if err != nil {
+				var zero R
+				return zero, err
+			}
+
+			defer release()
+
+			return handler(ctx, evt)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Drafteame/engine/handlers/apigatewayv2"
+)
+
+func TestRateLimit(t *testing.T) {
+	echo := func(_ context.Context, evt string) (string, error) {
+		return evt, nil
+	}
+
+	t.Run("should reject invocations above the burst until tokens refill", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+
+		store := NewMemoryRateLimitStore()
+		store.now = clock.Now
+
+		handler := RateLimitWithConfig(RateLimitConfig[string, string]{Rate: 2, Burst: 2, Store: store})(echo)
+
+		for range 2 {
+			_, err := handler(context.Background(), "hello")
+			assert.NoError(t, err)
+		}
+
+		_, err := handler(context.Background(), "hello")
+
+		var limitErr *RateLimitError
+
+		require.ErrorAs(t, err, &limitErr)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, 500*time.Millisecond, limitErr.RetryAfter)
+
+		clock.now = clock.now.Add(500 * time.Millisecond)
+
+		res, err := handler(context.Background(), "hello")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", res)
+	})
+
+	t.Run("should drop buckets that refilled", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+
+		store := NewMemoryRateLimitStore()
+		store.now = clock.Now
+
+		for _, key := range []string{"a", "b"} {
+			ok, _, err := store.Take(context.Background(), key, 2, 2)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+
+		_, _, err := store.Take(context.Background(), "slow", 0.001, 1)
+		require.NoError(t, err)
+
+		clock.now = clock.now.Add(2 * time.Minute)
+
+		_, _, err = store.Take(context.Background(), "c", 2, 2)
+		require.NoError(t, err)
+
+		assert.Len(t, store.buckets, 2)
+		assert.Contains(t, store.buckets, "slow")
+		assert.Contains(t, store.buckets, "c")
+	})
+
+	t.Run("should limit each tenant of the jwt claims separately", func(t *testing.T) {
+		handler := RateLimitWithConfig(RateLimitConfig[apigatewayv2.HTTPRequest, apigatewayv2.HTTPResponse]{
+			Rate: 0.001,
+			Key: func(_ context.Context, evt apigatewayv2.HTTPRequest) string {
+				if auth := evt.RequestContext.Authorizer; auth != nil && auth.JWT != nil {
+					return auth.JWT.Claims["tenant_id"]
+				}
+
+				return ""
+			},
+			OnLimit: func(context.Context, apigatewayv2.HTTPRequest, error) (apigatewayv2.HTTPResponse, error) {
+				return apigatewayv2.HTTPResponse{StatusCode: 429}, nil
+			},
+		})(func(context.Context, apigatewayv2.HTTPRequest) (apigatewayv2.HTTPResponse, error) {
+			return apigatewayv2.HTTPResponse{StatusCode: 200}, nil
+		})
+
+		request := func(tenant string) int {
+			res, err := handler(context.Background(), apigatewayv2.HTTPRequest{
+				RequestContext: apigatewayv2.HTTPRequestContext{
+					Authorizer: &apigatewayv2.HTTPRequestContextAuthorizerDescription{
+						JWT: &apigatewayv2.HTTPRequestContextAuthorizerJWTDescription{
+							Claims: map[string]string{"tenant_id": tenant},
+						},
+					},
+				},
+			})
+
+			assert.NoError(t, err)
+
+			return res.StatusCode
+		}
+
+		assert.Equal(t, 200, request("a"))
+		assert.Equal(t, 200, request("b"))
+		assert.Equal(t, 429, request("a"))
+
+		res, err := handler(context.Background(), apigatewayv2.HTTPRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+	})
+
+	t.Run("should wait for a token when configured", func(t *testing.T) {
+		handler := RateLimitWithConfig(RateLimitConfig[string, string]{Rate: 100, Wait: true})(echo)
+
+		start := time.Now()
+
+		for range 3 {
+			_, err := handler(context.Background(), "hello")
+			assert.NoError(t, err)
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	})
+
+	t.Run("should return store errors", func(t *testing.T) {
+		errStore := errors.New("store unavailable")
+
+		handler := RateLimitWithConfig(RateLimitConfig[string, string]{Rate: 1, Store: failingStore{err: errStore}})(echo)
+
+		_, err := handler(context.Background(), "hello")
+		assert.ErrorIs(t, err, errStore)
+	})
+}
+
+type failingStore struct {
+	err error
+}
+
+func (s failingStore) Take(context.Context, string, float64, int) (bool, time.Duration, error) {
+	return false, 0, s.err
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Run("should bound concurrent calls", func(t *testing.T) {
+		var (
+			inFlight, peak atomic.Int32
+			wg             sync.WaitGroup
+		)
+
+		limiter := NewConcurrencyLimiter(2)
+
+		for range 8 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_ = limiter.Do(context.Background(), "downstream", func(context.Context) error {
+					n := inFlight.Add(1)
+					defer inFlight.Add(-1)
+
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+
+					time.Sleep(5 * time.Millisecond)
+
+					return nil
+				})
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(2), peak.Load())
+		assert.Empty(t, limiter.slots)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		limiter := NewConcurrencyLimiter(1)
+
+		release, err := limiter.Acquire(context.Background(), "")
+		require.NoError(t, err)
+
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		handler := ConcurrencyLimit[string, string](limiter, nil)(func(_ context.Context, evt string) (string, error) {
+			return evt, nil
+		})
+
+		_, err = handler(ctx, "hello")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, limiter.slots[""].refs)
+	})
+}
@@ -2,10 +2,12 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"os"
 	"slices"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambda/messages"
 )
 
 // Handler is a function that is complaint to with the golang aws-lambda-go sdk.
@@ -17,6 +19,25 @@ type Handler[T, R any] func(context.Context, T) (R, error)
 // functionality.
 type Decorator[T, R any] func(handler Handler[T, R]) Handler[T, R]
 
+// InvokeError is an error reported to the Lambda runtime with an explicit error type instead of the Go type name.
+// It keeps the original error, so decorators can still match it with errors.Is and errors.As, and Run converts it
+// into the runtime error payload. Handlers started without Run must convert it themselves.
+type InvokeError struct {
+	Type    string
+	Message string
+	Err     error
+}
+
+// Error returns the message reported to the runtime.
+func (e *InvokeError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the original error.
+func (e *InvokeError) Unwrap() error {
+	return e.Err
+}
+
 // Engine is a struct that holds the handler and the decorators to be applied to the handler.
 type Engine[T, R any] struct {
 	handler    Handler[T, R]
@@ -41,7 +62,7 @@ func (e *Engine[T, R]) Run() {
 	e.applyDecorators()
 
 	if os.Getenv("AWS_LAMBDA_RUNTIME_API") != "" {
-		lambda.Start(e.handler)
+		lambda.Start(invokeErrors(e.handler))
 		return
 	}
 
@@ -55,3 +76,18 @@ func (e *Engine[T, R]) applyDecorators() {
 		e.handler = decorator(e.handler)
 	}
 }
+
+// invokeErrors converts InvokeError into the payload of the Lambda runtime, which only reads the error type from
+// messages.InvokeResponse_Error itself.
+func invokeErrors[T, R any](handler Handler[T, R]) Handler[T, R] {
+	return func(ctx context.Context, evt T) (R, error) {
+		res, err := handler(ctx, evt)
+
+		var invokeErr *InvokeError
+		if errors.As(err, &invokeErr) {
+			return res, messages.InvokeResponse_Error{Message: invokeErr.Message, Type: invokeErr.Type}
+		}
+
+		return res, err
+	}
+}
@@ -0,0 +1,10 @@
+package flags
+
+import "errors"
+
+var (
+	ErrMissingSource    = errors.New("flags: missing source")
+	ErrFetchingDocument = errors.New("flags: fetching document")
+	ErrDecodingDocument = errors.New("flags: decoding document")
+	ErrUnknownOperator  = errors.New("flags: unknown operator")
+)
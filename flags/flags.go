@@ -0,0 +1,177 @@
+// Package flags evaluates feature flags fetched from AppConfig or a local file, exposing them to handlers through the
+// context.
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"time"
+
+	"github.com/Drafteame/engine"
+	"github.com/Drafteame/engine/internal/refresh"
+)
+
+// DefaultPollInterval is how often the document is refreshed when Config.PollInterval is zero. It matches the default
+// poll interval of the AppConfig extension.
+const DefaultPollInterval = 45 * time.Second
+
+// Config is the configuration of a Client.
+type Config struct {
+	// Source provides the flag document. It is required.
+	Source Source
+
+	// PollInterval is how often the document is fetched again. The current document keeps being served while it is
+	// refreshed in the background. Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// OnError is called when fetching the document fails. Flags keep their last known state, or are disabled when the
+	// document was never fetched.
+	OnError func(error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Client keeps the flag document up to date. It should be created once per execution environment.
+type Client struct {
+	config Config
+	doc    *refresh.Value[Document]
+}
+
+// NewClient creates a Client for the given configuration.
+func NewClient(config Config) (*Client, error) {
+	if config.Source == nil {
+		return nil, ErrMissingSource
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	c := &Client{config: config}
+
+	c.doc = refresh.New(refresh.Config[Document]{
+		Fetch:   c.fetch,
+		MaxAge:  config.PollInterval,
+		OnError: config.OnError,
+		Now:     config.Now,
+	})
+
+	return c, nil
+}
+
+// Document returns the current flag document, fetching it synchronously the first time. Once fetched, a document
+// older than the poll interval is returned immediately while a refresh runs in the background.
+func (c *Client) Document(ctx context.Context) (Document, error) {
+	return c.doc.Get(ctx)
+}
+
+// Refresh fetches the flag document synchronously and returns it.
+func (c *Client) Refresh(ctx context.Context) (Document, error) {
+	return c.doc.Refresh(ctx)
+}
+
+func (c *Client) fetch(ctx context.Context) (Document, error) {
+	data, err := c.config.Source.Fetch(ctx)
+	if err != nil {
+		return nil, errors.Join(ErrFetchingDocument, err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Join(ErrDecodingDocument, err)
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+
+	if doc == nil {
+		doc = Document{}
+	}
+
+	return doc, nil
+}
+
+type evaluatorKey struct{}
+
+type evaluator struct {
+	doc   Document
+	attrs Attributes
+}
+
+// NewContext returns a copy of ctx evaluating flags of doc for the given attributes.
+func NewContext(ctx context.Context, doc Document, attrs Attributes) context.Context {
+	return context.WithValue(ctx, evaluatorKey{}, evaluator{doc: doc, attrs: attrs})
+}
+
+// WithAttributes returns a copy of ctx evaluating flags with the given attributes added, for example once the caller
+// of an HTTP request is authenticated.
+func WithAttributes(ctx context.Context, attrs Attributes) context.Context {
+	e, _ := ctx.Value(evaluatorKey{}).(evaluator)
+
+	merged := maps.Clone(e.attrs)
+	if merged == nil {
+		merged = make(Attributes, len(attrs))
+	}
+
+	maps.Copy(merged, attrs)
+
+	return NewContext(ctx, e.doc, merged)
+}
+
+// Get evaluates the named flag for the invocation of ctx, reporting whether it exists.
+func Get(ctx context.Context, name string) (Evaluation, bool) {
+	e, _ := ctx.Value(evaluatorKey{}).(evaluator)
+
+	f, ok := e.doc[name]
+	if !ok {
+		return Evaluation{}, false
+	}
+
+	return f.Evaluate(e.attrs), true
+}
+
+// Enabled reports whether the named flag is enabled for the invocation of ctx. Unknown flags are disabled.
+func Enabled(ctx context.Context, name string) bool {
+	eval, _ := Get(ctx, name)
+	return eval.Enabled
+}
+
+// Variant returns the variant of the named flag served to the invocation of ctx, or fallback when the flag is
+// unknown, disabled or has no variant.
+func Variant(ctx context.Context, name, fallback string) string {
+	eval, ok := Get(ctx, name)
+	if !ok || !eval.Enabled || eval.Variant == "" {
+		return fallback
+	}
+
+	return eval.Variant
+}
+
+// Decorator is a decorator that makes the flags of the client available to the handler through Enabled, Variant and
+// Get, evaluated for the attributes returned by the optional attributes function. When the document cannot be
+// fetched, the error goes to Config.OnError and every flag is disabled.
+func Decorator[T, R any](client *Client, attributes func(context.Context, T) Attributes) engine.Decorator[T, R] {
+	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
+		return func(ctx context.Context, evt T) (R, error) {
+			doc, err := client.Document(ctx)
+			if err != nil && client.config.OnError != nil {
+				client.config.OnError(err)
+			}
+
+			var attrs Attributes
+			if attributes != nil {
+				attrs = attributes(ctx, evt)
+			}
+
+			return handler(NewContext(ctx, doc, attrs), evt)
+		}
+	}
+}
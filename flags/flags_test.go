@@ -0,0 +1,200 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+const document = `{
+	"new-checkout": {
+		"enabled": false,
+		"rules": [
+			{"conditions": [{"attribute": "tenant", "operator": "in", "values": ["acme", "globex"]}], "enabled": true}
+		]
+	},
+	"theme": {
+		"enabled": true,
+		"variant": "light",
+		"rules": [
+			{"conditions": [{"attribute": "plan", "operator": "equals", "values": ["pro"]}, {"attribute": "beta", "operator": "exists"}], "variant": "dark"}
+		],
+		"color": "blue"
+	}
+}`
+
+func staticSource(calls *atomic.Int32, doc *atomic.Pointer[string]) Source {
+	return SourceFunc(func(context.Context) ([]byte, error) {
+		calls.Add(1)
+		return []byte(*doc.Load()), nil
+	})
+}
+
+func TestFlags(t *testing.T) {
+	t.Run("should evaluate flags for the event attributes", func(t *testing.T) {
+		var (
+			calls atomic.Int32
+			doc   atomic.Pointer[string]
+		)
+
+		raw := document
+		doc.Store(&raw)
+
+		client, err := NewClient(Config{Source: staticSource(&calls, &doc)})
+		require.NoError(t, err)
+
+		type result struct {
+			checkout bool
+			theme    string
+			color    any
+		}
+
+		handler := func(ctx context.Context, _ Attributes) (result, error) {
+			eval, _ := Get(ctx, "theme")
+			return result{Enabled(ctx, "new-checkout"), Variant(ctx, "theme", "none"), eval.Attributes["color"]}, nil
+		}
+
+		attributes := func(_ context.Context, evt Attributes) Attributes { return evt }
+
+		run := func(attrs Attributes) result {
+			res, err := testengine.New(context.Background(), attrs, handler).
+				Use(Decorator[Attributes, result](client, attributes)).
+				Run()
+
+			require.NoError(t, err)
+
+			return res
+		}
+
+		assert.Equal(t, result{true, "light", "blue"}, run(Attributes{"tenant": "acme"}))
+		assert.Equal(t, result{false, "light", "blue"}, run(Attributes{"tenant": "initech", "plan": "pro"}))
+		assert.Equal(t, result{false, "dark", "blue"}, run(Attributes{"plan": "pro", "beta": "1"}))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should add attributes during the invocation", func(t *testing.T) {
+		var doc Document
+		require.NoError(t, json.Unmarshal([]byte(document), &doc))
+
+		ctx := NewContext(context.Background(), doc, Attributes{"plan": "pro"})
+
+		assert.False(t, Enabled(ctx, "new-checkout"))
+		assert.True(t, Enabled(WithAttributes(ctx, Attributes{"tenant": "globex"}), "new-checkout"))
+		assert.Equal(t, "dark", Variant(WithAttributes(ctx, Attributes{"beta": "yes"}), "theme", ""))
+		assert.False(t, Enabled(context.Background(), "new-checkout"))
+	})
+
+	t.Run("should refresh the document after the poll interval", func(t *testing.T) {
+		var (
+			calls atomic.Int32
+			doc   atomic.Pointer[string]
+			now   atomic.Int64
+		)
+
+		raw := `{"beta": {"enabled": false}}`
+		doc.Store(&raw)
+		now.Store(time.Now().UnixNano())
+
+		client, err := NewClient(Config{
+			Source:       staticSource(&calls, &doc),
+			PollInterval: time.Minute,
+			Now:          func() time.Time { return time.Unix(0, now.Load()) },
+		})
+		require.NoError(t, err)
+
+		d, err := client.Document(context.Background())
+		require.NoError(t, err)
+		assert.False(t, d["beta"].Enabled)
+
+		updated := `{"beta": {"enabled": true}}`
+		doc.Store(&updated)
+		now.Add(int64(time.Minute))
+
+		d, _ = client.Document(context.Background())
+		assert.False(t, d["beta"].Enabled)
+
+		assert.Eventually(t, func() bool {
+			d, _ := client.Document(context.Background())
+			return d["beta"].Enabled
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should disable flags when the document cannot be fetched", func(t *testing.T) {
+		var reported error
+
+		errUnavailable := errors.New("unavailable")
+
+		client, err := NewClient(Config{
+			Source:  SourceFunc(func(context.Context) ([]byte, error) { return nil, errUnavailable }),
+			OnError: func(err error) { reported = err },
+		})
+		require.NoError(t, err)
+
+		handler := func(ctx context.Context, _ string) (bool, error) {
+			return Enabled(ctx, "beta"), nil
+		}
+
+		res, err := testengine.New(context.Background(), "hello", handler).
+			Use(Decorator[string, bool](client, nil)).
+			Run()
+
+		require.NoError(t, err)
+		assert.False(t, res)
+		assert.ErrorIs(t, reported, ErrFetchingDocument)
+		assert.ErrorIs(t, reported, errUnavailable)
+	})
+
+	t.Run("should reject unknown operators", func(t *testing.T) {
+		raw := `{"beta": {"enabled": true, "rules": [{"conditions": [{"attribute": "a", "operator": "gt"}]}]}}`
+
+		client, err := NewClient(Config{Source: SourceFunc(func(context.Context) ([]byte, error) { return []byte(raw), nil })})
+		require.NoError(t, err)
+
+		_, err = client.Refresh(context.Background())
+		assert.ErrorIs(t, err, ErrUnknownOperator)
+	})
+}
+
+func TestSources(t *testing.T) {
+	t.Run("should read from the appconfig extension", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/applications/shop/environments/prod/configurations/flags", r.URL.Path)
+			_, _ = w.Write([]byte(`{"beta":{"enabled":true}}`))
+		}))
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		t.Setenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT", u.Port())
+
+		data, err := AppConfig("shop", "prod", "flags", nil).Fetch(context.Background())
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"beta":{"enabled":true}}`, string(data))
+	})
+
+	t.Run("should read from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "flags.json")
+		require.NoError(t, os.WriteFile(path, []byte(document), 0o600))
+
+		client, err := NewClient(Config{Source: File(path)})
+		require.NoError(t, err)
+
+		doc, err := client.Document(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, doc, 2)
+	})
+}
@@ -0,0 +1,172 @@
+package flags
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Operators supported by conditions.
+const (
+	OpEquals    = "equals"
+	OpNotEquals = "not_equals"
+	OpIn        = "in"
+	OpNotIn     = "not_in"
+	OpExists    = "exists"
+	OpPrefix    = "prefix"
+	OpSuffix    = "suffix"
+	OpContains  = "contains"
+)
+
+// Attributes describe the invocation flags are evaluated for, such as the tenant or user id.
+type Attributes map[string]string
+
+// Document is a feature flag document keyed by flag name. It is compatible with the flag data returned by the
+// AppConfig extension, where each flag holds an "enabled" field and its attributes.
+type Document map[string]Flag
+
+// Flag is a boolean flag, or a multivariate one when it has variants.
+type Flag struct {
+	// Enabled is the state of the flag when no rule matches.
+	Enabled bool `json:"enabled"`
+
+	// Variant is the variant served when no rule matches.
+	Variant string `json:"variant,omitempty"`
+
+	// Rules are evaluated in order; the first matching rule overrides the state and variant of the flag.
+	Rules []Rule `json:"rules,omitempty"`
+
+	// Attributes holds the remaining fields of the flag, such as AppConfig flag attributes.
+	Attributes map[string]any `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields of the flag and keeps the others in Attributes.
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	type plain Flag
+
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for _, known := range []string{"enabled", "variant", "rules"} {
+		delete(all, known)
+	}
+
+	if len(all) > 0 {
+		p.Attributes = all
+	}
+
+	*f = Flag(p)
+
+	return nil
+}
+
+// Rule overrides a flag for the invocations matching all its conditions.
+type Rule struct {
+	Conditions []Condition `json:"conditions"`
+
+	// Enabled is the state of the flag when the rule matches. The flag state is kept when nil.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Variant is the variant served when the rule matches. The flag variant is kept when empty.
+	Variant string `json:"variant,omitempty"`
+}
+
+// Condition compares an invocation attribute with the given values.
+type Condition struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values,omitempty"`
+}
+
+// Evaluation is the result of evaluating a flag for an invocation.
+type Evaluation struct {
+	Enabled    bool
+	Variant    string
+	Attributes map[string]any
+}
+
+// Evaluate returns the state and variant of the flag for the given attributes.
+func (f Flag) Evaluate(attrs Attributes) Evaluation {
+	eval := Evaluation{Enabled: f.Enabled, Variant: f.Variant, Attributes: f.Attributes}
+
+	for _, r := range f.Rules {
+		if !r.matches(attrs) {
+			continue
+		}
+
+		if r.Enabled != nil {
+			eval.Enabled = *r.Enabled
+		}
+
+		if r.Variant != "" {
+			eval.Variant = r.Variant
+		}
+
+		break
+	}
+
+	return eval
+}
+
+func (r Rule) matches(attrs Attributes) bool {
+	for _, c := range r.Conditions {
+		if !c.matches(attrs) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Condition) matches(attrs Attributes) bool {
+	v, ok := attrs[c.Attribute]
+
+	switch c.Operator {
+	case OpExists:
+		return ok
+	case OpNotEquals, OpNotIn:
+		return !ok || !slices.Contains(c.Values, v)
+	}
+
+	if !ok {
+		return false
+	}
+
+	switch c.Operator {
+	case OpEquals, OpIn:
+		return slices.Contains(c.Values, v)
+	case OpPrefix:
+		return slices.ContainsFunc(c.Values, func(p string) bool { return strings.HasPrefix(v, p) })
+	case OpSuffix:
+		return slices.ContainsFunc(c.Values, func(s string) bool { return strings.HasSuffix(v, s) })
+	case OpContains:
+		return slices.ContainsFunc(c.Values, func(s string) bool { return strings.Contains(v, s) })
+	default:
+		return false
+	}
+}
+
+// validate reports conditions with unknown operators, which would otherwise never match.
+func (d Document) validate() error {
+	for name, f := range d {
+		for _, r := range f.Rules {
+			for _, c := range r.Conditions {
+				switch c.Operator {
+				case OpEquals, OpNotEquals, OpIn, OpNotIn, OpExists, OpPrefix, OpSuffix, OpContains:
+				default:
+					return fmt.Errorf("%w: %q in flag %s", ErrUnknownOperator, c.Operator, name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
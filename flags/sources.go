@@ -0,0 +1,81 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultAppConfigPort is the port of the AppConfig Lambda extension when AWS_APPCONFIG_EXTENSION_HTTP_PORT is not set.
+const DefaultAppConfigPort = "2772"
+
+// Source fetches the raw flag document.
+type Source interface {
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(ctx context.Context) ([]byte, error)
+
+// Fetch implements Source.
+func (f SourceFunc) Fetch(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// HTTPClient sends the requests of the AppConfig source. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// AppConfig returns a source reading the configuration profile of the given application and environment from the
+// localhost endpoint of the AppConfig Lambda extension. The client defaults to http.DefaultClient.
+func AppConfig(application, environment, profile string, client HTTPClient) Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	path := "/applications/" + url.PathEscape(application) +
+		"/environments/" + url.PathEscape(environment) +
+		"/configurations/" + url.PathEscape(profile)
+
+	return SourceFunc(func(ctx context.Context) ([]byte, error) {
+		port := os.Getenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT")
+		if port == "" {
+			port = DefaultAppConfigPort
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:"+port+path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = res.Body.Close() }()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		return body, nil
+	})
+}
+
+// File returns a source reading the document from a local JSON file.
+func File(path string) Source {
+	return SourceFunc(func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	})
+}
@@ -3,19 +3,39 @@ package apigatewayv1
 import (
 	"context"
 	"errors"
+	"maps"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/Drafteame/engine"
 	"github.com/Drafteame/engine/internal/request"
 	"github.com/Drafteame/engine/internal/response"
+	"github.com/Drafteame/engine/logger"
+	"github.com/Drafteame/engine/middleware/jwt"
 )
 
 var (
 	ErrParsingPathFailed = errors.New("failed to parse path")
 )
 
+// DefaultTimeoutMargin is the TimeoutMargin of DefaultConfig.
+const DefaultTimeoutMargin = request.DefaultTimeoutMargin
+
+// Config is the configuration of the API gateway handler.
+type Config = request.GatewayConfig
+
+// DefaultConfig returns the default configuration of the API gateway handler.
+func DefaultConfig() Config {
+	return request.DefaultGatewayConfig()
+}
+
 func NewHandler(handler http.Handler) engine.Handler[HTTPRequest, HTTPResponse] {
+	return NewHandlerWithConfig(handler, DefaultConfig())
+}
+
+// NewHandlerWithConfig creates an API gateway handler serving requests with the given http.Handler and configuration.
+func NewHandlerWithConfig(handler http.Handler, config Config) engine.Handler[HTTPRequest, HTTPResponse] {
 	return func(ctx context.Context, evt HTTPRequest) (HTTPResponse, error) {
 		u, err := url.Parse(evt.Path)
 		if err != nil {
@@ -32,6 +52,15 @@ func NewHandler(handler http.Handler) engine.Handler[HTTPRequest, HTTPResponse]
 			q[k] = values
 		}
 
+		ctx = logger.With(ctx, "method", evt.HTTPMethod, "path", evt.Path, "route", evt.Resource)
+
+		if claims, ok := authorizerClaims(evt.RequestContext.Authorizer); ok {
+			ctx = jwt.WithClaims(ctx, claims)
+		}
+
+		ctx, cancel := request.WithTimeoutMargin(ctx, config.TimeoutMargin)
+		defer cancel()
+
 		req, err := request.New(ctx, request.Config{
 			Path:        evt.Path,
 			QueryString: q.Encode(),
@@ -44,16 +73,67 @@ func NewHandler(handler http.Handler) engine.Handler[HTTPRequest, HTTPResponse]
 			MultiHeader: evt.MultiValueHeaders,
 			RequestID:   evt.RequestContext.RequestID,
 			Stage:       evt.RequestContext.Stage,
+
+			PathParameters: evt.PathParameters,
+			Route:          evt.Resource,
+			BasePath:       request.BasePath(config.StripStage, config.BasePath, evt.RequestContext.Stage),
+			DomainName:     evt.RequestContext.DomainName,
 		})
 
 		if err != nil {
 			return HTTPResponse{}, err
 		}
 
-		res := response.New(new(HTTPResponse))
+		res := response.New(req, new(HTTPResponse))
 
 		handler.ServeHTTP(res, req)
 
 		return *res.End(), nil
 	}
 }
+
+// OriginalPathFrom returns the path of the request of ctx as received by API Gateway, before stripping the stage or
+// base path.
+func OriginalPathFrom(ctx context.Context) (string, bool) {
+	return request.OriginalPathFrom(ctx)
+}
+
+// ResourceFrom returns the API Gateway resource matched for the request of ctx, such as "/items/{id}".
+func ResourceFrom(ctx context.Context) (string, bool) {
+	return request.RouteFrom(ctx)
+}
+
+// authorizerClaims extracts the claims forwarded by Cognito user pool authorizers of REST APIs, or by JWT authorizers
+// of HTTP APIs using the 1.0 payload format.
+func authorizerClaims(authorizer map[string]any) (jwt.Claims, bool) {
+	if claims, ok := authorizer["claims"].(map[string]any); ok {
+		return jwt.Claims(maps.Clone(claims)), true
+	}
+
+	desc, ok := authorizer["jwt"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := desc["claims"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	// copied, so adding the scope does not change the event
+	out := jwt.Claims(maps.Clone(claims))
+
+	if scopes, ok := desc["scopes"].([]any); ok && out["scope"] == nil {
+		list := make([]string, 0, len(scopes))
+
+		for _, s := range scopes {
+			if str, ok := s.(string); ok {
+				list = append(list, str)
+			}
+		}
+
+		out["scope"] = strings.Join(list, " ")
+	}
+
+	return out, true
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Drafteame/engine/middleware/jwt"
 	testengine "github.com/Drafteame/engine/test/engine"
 )
 
@@ -27,4 +28,162 @@ func TestNewHandler(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusOK, res.StatusCode)
 	})
+
+	t.Run("should expose path parameters and the matched resource", func(t *testing.T) {
+		var id, proxy, resource string
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = r.PathValue("id")
+			resource, _ = ResourceFrom(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		s := http.NewServeMux()
+		s.HandleFunc("GET /items/{rest...}", func(w http.ResponseWriter, r *http.Request) {
+			proxy = r.PathValue("rest")
+			handler(w, r)
+		})
+
+		evt := HTTPRequest{
+			Resource:       "/items/{id}",
+			Path:           "/items/42",
+			HTTPMethod:     "GET",
+			PathParameters: map[string]string{"id": "42"},
+		}
+
+		res, err := testengine.New(context.TODO(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "42", id)
+		assert.Equal(t, "42", proxy)
+		assert.Equal(t, "/items/{id}", resource)
+	})
+
+	t.Run("should strip the base path", func(t *testing.T) {
+		cases := map[string]string{
+			"/v1/items": "/items",
+			"/v1":       "/",
+			"/v10/x":    "/v10/x",
+		}
+
+		for in, want := range cases {
+			var path string
+
+			s := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.WriteHeader(http.StatusOK)
+			})
+
+			evt := HTTPRequest{Path: in, HTTPMethod: "GET"}
+
+			_, err := testengine.New(context.TODO(), evt, NewHandlerWithConfig(s, Config{BasePath: "/v1/"})).Run()
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, path, in)
+		}
+	})
+
+	t.Run("should rebuild the request from the forwarding headers", func(t *testing.T) {
+		var req *http.Request
+
+		s := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			Path:       "/items",
+			HTTPMethod: "GET",
+			Headers: map[string]string{
+				"X-Forwarded-For":   "198.51.100.7, 203.0.113.10",
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Port":  "8443",
+			},
+			RequestContext: HTTPRequestContext{
+				DomainName: "api.example.com",
+				Identity:   HTTPRequestIdentity{SourceIP: "203.0.113.10"},
+			},
+		}
+
+		_, err := testengine.New(context.TODO(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "api.example.com:8443", req.Host)
+		assert.Equal(t, "203.0.113.10:0", req.RemoteAddr)
+		assert.NotNil(t, req.TLS)
+		assert.Equal(t, "api.example.com", req.TLS.ServerName)
+		assert.Equal(t, "198.51.100.7, 203.0.113.10", req.Header.Get("X-Forwarded-For"))
+	})
+
+	t.Run("should expose cognito authorizer claims", func(t *testing.T) {
+		var claims jwt.Claims
+
+		s := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ = jwt.ClaimsFrom(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			Path:       "/test",
+			HTTPMethod: "GET",
+			RequestContext: HTTPRequestContext{
+				Authorizer: map[string]any{
+					"claims": map[string]any{"sub": "user-42", "scope": "read write"},
+				},
+			},
+		}
+
+		_, err := testengine.New(context.Background(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-42", claims.Subject())
+		assert.True(t, claims.HasScopes("read", "write"))
+	})
+
+	t.Run("should expose jwt authorizer claims without changing the event", func(t *testing.T) {
+		var claims jwt.Claims
+
+		s := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ = jwt.ClaimsFrom(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		eventClaims := map[string]any{"sub": "user-42"}
+
+		evt := HTTPRequest{
+			Path:       "/test",
+			HTTPMethod: "GET",
+			RequestContext: HTTPRequestContext{
+				Authorizer: map[string]any{
+					"jwt": map[string]any{
+						"claims": eventClaims,
+						"scopes": []any{"read", "write"},
+					},
+				},
+			},
+		}
+
+		_, err := testengine.New(context.Background(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-42", claims.Subject())
+		assert.True(t, claims.HasScopes("read", "write"))
+		assert.Equal(t, map[string]any{"sub": "user-42"}, eventClaims)
+	})
+
+	t.Run("should send cookies as a multi-value header", func(t *testing.T) {
+		s := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{Path: "/test", HTTPMethod: "GET"}
+
+		res, err := testengine.New(context.Background(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"session=abc"}, res.MultiValueHeaders["Set-Cookie"])
+		assert.NotContains(t, res.Headers, "Set-Cookie")
+	})
 }
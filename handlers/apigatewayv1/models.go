@@ -1,6 +1,9 @@
 package apigatewayv1
 
-import "github.com/Drafteame/engine/internal/response"
+import (
+	"github.com/Drafteame/engine/internal/response"
+	"github.com/Drafteame/engine/redact"
+)
 
 // HTTPRequest contains data coming from the API Gateway proxy
 type HTTPRequest struct {
@@ -85,8 +88,35 @@ func (r *HTTPResponse) SetIsBase64Encoded(b64 bool) {
 	r.IsBase64Encoded = b64
 }
 
-func (r *HTTPResponse) SetCookies(_ []string) {
-	// Not supported in API Gateway V1
+// SetCookies adds the cookies as a multi-value Set-Cookie header, since API Gateway V1 has no cookies field.
+func (r *HTTPResponse) SetCookies(cookies []string) {
+	if len(cookies) == 0 {
+		return
+	}
+
+	if r.MultiValueHeaders == nil {
+		r.MultiValueHeaders = make(map[string][]string)
+	}
+
+	r.MultiValueHeaders["Set-Cookie"] = cookies
 }
 
 var _ response.Out = (*HTTPResponse)(nil)
+
+// Redaction returns the redaction preset used when logging requests and responses: the default sensitive headers
+// plus bodies, authorizer data and caller identity.
+func (HTTPRequest) Redaction() redact.Config {
+	return redact.Default().Merge(redact.Config{
+		Paths: []string{
+			"body",
+			"requestContext.authorizer",
+			"requestContext.identity.apiKey",
+			"requestContext.identity.accessKey",
+			"requestContext.identity.caller",
+			"requestContext.identity.user",
+			"requestContext.identity.userArn",
+			"requestContext.identity.cognitoIdentityId",
+			"requestContext.identity.cognitoAuthenticationProvider",
+		},
+	})
+}
@@ -8,15 +8,42 @@ import (
 	"github.com/Drafteame/engine"
 	"github.com/Drafteame/engine/internal/request"
 	"github.com/Drafteame/engine/internal/response"
+	"github.com/Drafteame/engine/logger"
+	"github.com/Drafteame/engine/middleware/jwt"
 )
 
+// DefaultTimeoutMargin is the TimeoutMargin of DefaultConfig.
+const DefaultTimeoutMargin = request.DefaultTimeoutMargin
+
+// Config is the configuration of the API gateway handler.
+type Config = request.GatewayConfig
+
+// DefaultConfig returns the default configuration of the API gateway handler.
+func DefaultConfig() Config {
+	return request.DefaultGatewayConfig()
+}
+
 func NewHandler(handler http.Handler) engine.Handler[HTTPRequest, HTTPResponse] {
+	return NewHandlerWithConfig(handler, DefaultConfig())
+}
+
+// NewHandlerWithConfig creates an API gateway handler serving requests with the given http.Handler and configuration.
+func NewHandlerWithConfig(handler http.Handler, config Config) engine.Handler[HTTPRequest, HTTPResponse] {
 	return func(ctx context.Context, evt HTTPRequest) (HTTPResponse, error) {
 		multiHeader := make(map[string][]string)
 		for k, values := range evt.Headers {
 			multiHeader[k] = strings.Split(values, ",")
 		}
 
+		ctx = logger.With(ctx, "method", evt.RequestContext.HTTP.Method, "path", evt.RawPath, "route", evt.RouteKey)
+
+		if auth := evt.RequestContext.Authorizer; auth != nil && auth.JWT != nil {
+			ctx = jwt.WithClaims(ctx, jwt.FromGateway(auth.JWT.Claims, auth.JWT.Scopes))
+		}
+
+		ctx, cancel := request.WithTimeoutMargin(ctx, config.TimeoutMargin)
+		defer cancel()
+
 		req, err := request.New(ctx, request.Config{
 			Path:        evt.RawPath,
 			QueryString: evt.RawQueryString,
@@ -29,16 +56,32 @@ func NewHandler(handler http.Handler) engine.Handler[HTTPRequest, HTTPResponse]
 			Cookies:     evt.Cookies,
 			RequestID:   evt.RequestContext.RequestID,
 			Stage:       evt.RequestContext.Stage,
+
+			PathParameters: evt.PathParameters,
+			Route:          evt.RouteKey,
+			BasePath:       request.BasePath(config.StripStage, config.BasePath, evt.RequestContext.Stage),
+			DomainName:     evt.RequestContext.DomainName,
 		})
 
 		if err != nil {
 			return HTTPResponse{}, err
 		}
 
-		res := response.New(new(HTTPResponse))
+		res := response.New(req, new(HTTPResponse))
 
 		handler.ServeHTTP(res, req)
 
 		return *res.End(), nil
 	}
 }
+
+// OriginalPathFrom returns the path of the request of ctx as received by API Gateway, before stripping the stage or
+// base path.
+func OriginalPathFrom(ctx context.Context) (string, bool) {
+	return request.OriginalPathFrom(ctx)
+}
+
+// RouteKeyFrom returns the API Gateway route key matched for the request of ctx, such as "GET /items/{id}".
+func RouteKeyFrom(ctx context.Context) (string, bool) {
+	return request.RouteFrom(ctx)
+}
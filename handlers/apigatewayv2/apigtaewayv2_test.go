@@ -1,12 +1,17 @@
 package apigatewayv2
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Drafteame/engine/logger"
+	"github.com/Drafteame/engine/middleware/jwt"
 	testengine "github.com/Drafteame/engine/test/engine"
 )
 
@@ -32,4 +37,184 @@ func TestNewHandler(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusOK, res.StatusCode)
 	})
+
+	t.Run("should expose jwt authorizer claims", func(t *testing.T) {
+		var claims jwt.Claims
+
+		s := http.NewServeMux()
+		s.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+			claims, _ = jwt.ClaimsFrom(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			RawPath: "/test",
+			RequestContext: HTTPRequestContext{
+				HTTP: HTTPRequestContextHTTPDescription{
+					Method: "GET",
+					Path:   "/test",
+				},
+				Authorizer: &HTTPRequestContextAuthorizerDescription{
+					JWT: &HTTPRequestContextAuthorizerJWTDescription{
+						Claims: map[string]string{"sub": "user-42"},
+						Scopes: []string{"read"},
+					},
+				},
+			},
+		}
+
+		_, err := testengine.New(context.Background(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-42", claims.Subject())
+		assert.True(t, claims.HasScopes("read"))
+	})
+
+	t.Run("should tag the request logger with route fields", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		s := http.NewServeMux()
+		s.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+			logger.From(r.Context()).Info("handled")
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			RouteKey: "GET /items/{id}",
+			RawPath:  "/items/1",
+			RequestContext: HTTPRequestContext{
+				HTTP: HTTPRequestContextHTTPDescription{
+					Method: "GET",
+					Path:   "/items/1",
+				},
+			},
+		}
+
+		ctx := logger.NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+		_, err := testengine.New(ctx, evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), `method=GET path=/items/1 route="GET /items/{id}"`)
+	})
+
+	t.Run("should expose path parameters and the matched route key", func(t *testing.T) {
+		var id, routeKey string
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = r.PathValue("id")
+			routeKey, _ = RouteKeyFrom(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			RouteKey:       "GET /items/{id}",
+			RawPath:        "/items/42",
+			PathParameters: map[string]string{"id": "42"},
+			RequestContext: HTTPRequestContext{
+				HTTP: HTTPRequestContextHTTPDescription{
+					Method: "GET",
+					Path:   "/items/42",
+				},
+			},
+		}
+
+		res, err := testengine.New(context.Background(), evt, NewHandler(handler)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "42", id)
+		assert.Equal(t, "GET /items/{id}", routeKey)
+	})
+
+	t.Run("should strip the stage and keep the original path", func(t *testing.T) {
+		var path, original string
+
+		s := http.NewServeMux()
+		s.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			original, _ = OriginalPathFrom(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			RawPath:        "/prod/items",
+			RawQueryString: "page=2",
+			RequestContext: HTTPRequestContext{
+				Stage: "prod",
+				HTTP: HTTPRequestContextHTTPDescription{
+					Method: "GET",
+					Path:   "/prod/items",
+				},
+			},
+		}
+
+		res, err := testengine.New(context.Background(), evt, NewHandlerWithConfig(s, Config{StripStage: true})).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "/items", path)
+		assert.Equal(t, "/prod/items", original)
+	})
+
+	t.Run("should rebuild the request from the forwarding headers", func(t *testing.T) {
+		var req *http.Request
+
+		s := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			RawPath: "/items",
+			Headers: map[string]string{
+				"host":              "api.example.com",
+				"x-forwarded-for":   "198.51.100.7, 203.0.113.10",
+				"x-forwarded-proto": "http",
+				"x-forwarded-port":  "80",
+			},
+			RequestContext: HTTPRequestContext{
+				DomainName: "abc123.execute-api.us-east-1.amazonaws.com",
+				HTTP: HTTPRequestContextHTTPDescription{
+					Method:   "GET",
+					Path:     "/items",
+					SourceIP: "203.0.113.10",
+				},
+			},
+		}
+
+		_, err := testengine.New(context.Background(), evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "api.example.com", req.Host)
+		assert.Equal(t, "203.0.113.10:0", req.RemoteAddr)
+		assert.Nil(t, req.TLS)
+		assert.Equal(t, "198.51.100.7, 203.0.113.10", req.Header.Get("X-Forwarded-For"))
+	})
+
+	t.Run("should cancel the request context before the invocation deadline", func(t *testing.T) {
+		var deadline time.Time
+
+		s := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, _ = r.Context().Deadline()
+			w.WriteHeader(http.StatusOK)
+		})
+
+		evt := HTTPRequest{
+			RawPath: "/test",
+			RequestContext: HTTPRequestContext{
+				HTTP: HTTPRequestContextHTTPDescription{Method: "GET", Path: "/test"},
+			},
+		}
+
+		invocationDeadline := time.Now().Add(time.Minute)
+
+		ctx, cancel := context.WithDeadline(context.Background(), invocationDeadline)
+		defer cancel()
+
+		_, err := testengine.New(ctx, evt, NewHandler(s)).Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, invocationDeadline.Add(-DefaultTimeoutMargin), deadline)
+	})
 }
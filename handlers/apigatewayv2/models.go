@@ -1,6 +1,9 @@
 package apigatewayv2
 
-import "github.com/Drafteame/engine/internal/response"
+import (
+	"github.com/Drafteame/engine/internal/response"
+	"github.com/Drafteame/engine/redact"
+)
 
 // HTTPRequest contains data coming from the new HTTP API Gateway
 type HTTPRequest struct {
@@ -129,3 +132,16 @@ func (r *HTTPResponse) SetCookies(cookies []string) {
 }
 
 var _ response.Out = (*HTTPResponse)(nil)
+
+// Redaction returns the redaction preset used when logging requests and responses: the default sensitive headers
+// plus bodies, cookies, authorizer data and client certificates.
+func (HTTPRequest) Redaction() redact.Config {
+	return redact.Default().Merge(redact.Config{
+		Paths: []string{
+			"body",
+			"cookies",
+			"requestContext.authorizer",
+			"requestContext.authentication",
+		},
+	})
+}
@@ -0,0 +1,236 @@
+package appsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Drafteame/engine"
+)
+
+// ResolverFunc resolves a single field.
+type ResolverFunc[A, S, R any] func(context.Context, Request[A, S]) (R, error)
+
+// BatchResolverFunc resolves a field for a batch of parent objects at once. It must return one result per request,
+// in the same order.
+type BatchResolverFunc[A, S, R any] func(context.Context, []Request[A, S]) ([]Result[R], error)
+
+type resolver struct {
+	single func(context.Context, Event) (any, error)
+	batch  func(context.Context, []Event) []Result[any]
+}
+
+// Router sends resolver events to the resolver registered for their "parentTypeName.fieldName".
+type Router struct {
+	resolvers map[string]resolver
+}
+
+// NewRouter creates an empty Router.
+func NewRouter() *Router {
+	return &Router{resolvers: make(map[string]resolver)}
+}
+
+// Resolve registers a resolver for the given type and field. Batch invocations of the field call it once per item.
+func Resolve[A, S, R any](r *Router, typeName, fieldName string, fn ResolverFunc[A, S, R]) {
+	single := func(ctx context.Context, evt Event) (any, error) {
+		req, err := decodeRequest[A, S](evt)
+		if err != nil {
+			return nil, err
+		}
+
+		return fn(ctx, req)
+	}
+
+	r.resolvers[typeName+"."+fieldName] = resolver{
+		single: single,
+		batch: func(ctx context.Context, evts []Event) []Result[any] {
+			results := make([]Result[any], len(evts))
+
+			for i, evt := range evts {
+				data, err := single(ctx, evt)
+				results[i] = Result[any]{Data: data, Err: err}
+			}
+
+			return results
+		},
+	}
+}
+
+// ResolveBatch registers a batch resolver for the given type and field. Single invocations of the field call it
+// with a batch of one item.
+func ResolveBatch[A, S, R any](r *Router, typeName, fieldName string, fn BatchResolverFunc[A, S, R]) {
+	batch := func(ctx context.Context, evts []Event) []Result[any] {
+		results := make([]Result[any], len(evts))
+		reqs := make([]Request[A, S], 0, len(evts))
+		idx := make([]int, 0, len(evts))
+
+		for i, evt := range evts {
+			req, err := decodeRequest[A, S](evt)
+			if err != nil {
+				results[i] = Result[any]{Err: err}
+				continue
+			}
+
+			reqs = append(reqs, req)
+			idx = append(idx, i)
+		}
+
+		if len(reqs) == 0 {
+			return results
+		}
+
+		out, err := fn(ctx, reqs)
+		if err == nil && len(out) != len(reqs) {
+			err = ErrBatchSizeMismatch
+		}
+
+		for j, i := range idx {
+			if err != nil {
+				results[i] = Result[any]{Err: err}
+				continue
+			}
+
+			results[i] = Result[any]{Data: out[j].Data, Err: out[j].Err}
+		}
+
+		return results
+	}
+
+	r.resolvers[typeName+"."+fieldName] = resolver{
+		batch: batch,
+		single: func(ctx context.Context, evt Event) (any, error) {
+			res := batch(ctx, []Event{evt})[0]
+			return res.Data, res.Err
+		},
+	}
+}
+
+// NewHandler creates a direct Lambda resolver handler for the given router, accepting both single and batch
+// invocations. Errors of single invocations are reported with the type of an *Error as "errorType"; batch
+// invocations also report its "errorInfo", and only fail the items whose resolver failed.
+func NewHandler(r *Router) engine.Handler[json.RawMessage, any] {
+	return func(ctx context.Context, payload json.RawMessage) (any, error) {
+		if bytes.HasPrefix(bytes.TrimSpace(payload), []byte("[")) {
+			var evts []Event
+			if err := json.Unmarshal(payload, &evts); err != nil {
+				return nil, errors.Join(err, ErrInvalidEvent)
+			}
+
+			return r.resolveBatch(ctx, evts), nil
+		}
+
+		var evt Event
+		if err := json.Unmarshal(payload, &evt); err != nil {
+			return nil, errors.Join(err, ErrInvalidEvent)
+		}
+
+		res, ok := r.resolvers[key(evt)]
+		if !ok {
+			return nil, ErrResolverNotFound
+		}
+
+		data, err := res.single(ctx, evt)
+		if err != nil {
+			return nil, invokeError(err)
+		}
+
+		return data, nil
+	}
+}
+
+func (r *Router) resolveBatch(ctx context.Context, evts []Event) []batchItem {
+	items := make([]batchItem, len(evts))
+	groups := make(map[string][]int)
+	var order []string
+
+	for i, evt := range evts {
+		k := key(evt)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+
+		groups[k] = append(groups[k], i)
+	}
+
+	for _, k := range order {
+		idx := groups[k]
+
+		res, ok := r.resolvers[k]
+		if !ok {
+			for _, i := range idx {
+				items[i] = toBatchItem(Result[any]{Err: ErrResolverNotFound})
+			}
+
+			continue
+		}
+
+		group := make([]Event, len(idx))
+		for j, i := range idx {
+			group[j] = evts[i]
+		}
+
+		for j, result := range res.batch(ctx, group) {
+			items[idx[j]] = toBatchItem(result)
+		}
+	}
+
+	return items
+}
+
+func key(evt Event) string {
+	return evt.Info.ParentTypeName + "." + evt.Info.FieldName
+}
+
+func decodeRequest[A, S any](evt Event) (Request[A, S], error) {
+	req := Request[A, S]{Event: evt}
+
+	if err := decodeRaw(evt.Arguments, &req.Arguments); err != nil {
+		return req, err
+	}
+
+	if err := decodeRaw(evt.Source, &req.Source); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func decodeRaw(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errors.Join(err, ErrInvalidEvent)
+	}
+
+	return nil
+}
+
+func toBatchItem(res Result[any]) batchItem {
+	if res.Err == nil {
+		return batchItem{Data: res.Data}
+	}
+
+	item := batchItem{ErrorMessage: res.Err.Error()}
+
+	var gqlErr *Error
+	if errors.As(res.Err, &gqlErr) {
+		item.ErrorMessage = gqlErr.Message
+		item.ErrorType = gqlErr.Type
+		item.ErrorInfo = gqlErr.Info
+	}
+
+	return item
+}
+
+// invokeError attaches the type of GraphQL errors to err, which AppSync reports verbatim.
+func invokeError(err error) error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) && gqlErr.Type != "" {
+		return &engine.InvokeError{Type: gqlErr.Type, Message: gqlErr.Message, Err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,135 @@
+package appsync
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Drafteame/engine"
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type getPostArgs struct {
+	ID string `json:"id"`
+}
+
+type post struct {
+	ID       string `json:"id"`
+	AuthorID string `json:"authorId"`
+}
+
+type author struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newRouter(authorCalls *int) *Router {
+	r := NewRouter()
+
+	Resolve(r, "Query", "getPost", func(_ context.Context, req Request[getPostArgs, struct{}]) (post, error) {
+		if req.Arguments.ID == "missing" {
+			return post{}, NewError("NotFound", "post not found", map[string]any{"id": req.Arguments.ID})
+		}
+
+		return post{ID: req.Arguments.ID, AuthorID: "a-" + req.Arguments.ID}, nil
+	})
+
+	ResolveBatch(r, "Post", "author", func(_ context.Context, reqs []Request[struct{}, post]) ([]Result[author], error) {
+		*authorCalls++
+
+		out := make([]Result[author], len(reqs))
+		for i, req := range reqs {
+			if req.Source.AuthorID == "a-banned" {
+				out[i] = Result[author]{Err: NewError("Forbidden", "author hidden", nil)}
+				continue
+			}
+
+			out[i] = Result[author]{Data: author{ID: req.Source.AuthorID, Name: "Author " + req.Source.AuthorID}}
+		}
+
+		return out, nil
+	})
+
+	return r
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should resolve single invocation", func(t *testing.T) {
+		calls := 0
+		payload := json.RawMessage(`{"arguments":{"id":"1"},"info":{"parentTypeName":"Query","fieldName":"getPost"}}`)
+
+		res, err := testengine.New(context.Background(), payload, NewHandler(newRouter(&calls))).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, post{ID: "1", AuthorID: "a-1"}, res)
+	})
+
+	t.Run("should return graphql error type", func(t *testing.T) {
+		calls := 0
+		payload := json.RawMessage(`{"arguments":{"id":"missing"},"info":{"parentTypeName":"Query","fieldName":"getPost"}}`)
+
+		_, err := testengine.New(context.Background(), payload, NewHandler(newRouter(&calls))).Run()
+
+		var invokeErr *engine.InvokeError
+		require.ErrorAs(t, err, &invokeErr)
+		assert.Equal(t, "NotFound", invokeErr.Type)
+		assert.Equal(t, "post not found", invokeErr.Message)
+
+		var gqlErr *Error
+		require.ErrorAs(t, err, &gqlErr)
+		assert.Equal(t, map[string]any{"id": "missing"}, gqlErr.Info)
+	})
+
+	t.Run("should fail on unknown field", func(t *testing.T) {
+		calls := 0
+		payload := json.RawMessage(`{"info":{"parentTypeName":"Query","fieldName":"unknown"}}`)
+
+		_, err := testengine.New(context.Background(), payload, NewHandler(newRouter(&calls))).Run()
+
+		assert.ErrorIs(t, err, ErrResolverNotFound)
+	})
+
+	t.Run("should resolve batch invocation at once", func(t *testing.T) {
+		calls := 0
+		payload := json.RawMessage(`[
+			{"source":{"id":"1","authorId":"a-1"},"info":{"parentTypeName":"Post","fieldName":"author"}},
+			{"source":{"id":"2","authorId":"a-banned"},"info":{"parentTypeName":"Post","fieldName":"author"}},
+			{"source":{"id":"3","authorId":"a-3"},"info":{"parentTypeName":"Post","fieldName":"author"}}
+		]`)
+
+		res, err := testengine.New(context.Background(), payload, NewHandler(newRouter(&calls))).Run()
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+
+		b, err := json.Marshal(res)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `[
+			{"data":{"id":"a-1","name":"Author a-1"}},
+			{"data":null,"errorMessage":"author hidden","errorType":"Forbidden"},
+			{"data":{"id":"a-3","name":"Author a-3"}}
+		]`, string(b))
+	})
+
+	t.Run("should resolve batch invocation with single resolver", func(t *testing.T) {
+		calls := 0
+		payload := json.RawMessage(`[
+			{"arguments":{"id":"1"},"info":{"parentTypeName":"Query","fieldName":"getPost"}},
+			{"arguments":{"id":"missing"},"info":{"parentTypeName":"Query","fieldName":"getPost"}}
+		]`)
+
+		res, err := testengine.New(context.Background(), payload, NewHandler(newRouter(&calls))).Run()
+		require.NoError(t, err)
+
+		b, err := json.Marshal(res)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `[
+			{"data":{"id":"1","authorId":"a-1"}},
+			{"data":null,"errorMessage":"post not found","errorType":"NotFound","errorInfo":{"id":"missing"}}
+		]`, string(b))
+	})
+}
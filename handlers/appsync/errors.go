@@ -0,0 +1,27 @@
+package appsync
+
+import "errors"
+
+var (
+	ErrResolverNotFound  = errors.New("appsync: resolver not found")
+	ErrInvalidEvent      = errors.New("appsync: invalid event")
+	ErrBatchSizeMismatch = errors.New("appsync: batch resolver returned a different number of results")
+)
+
+// Error is a GraphQL error with an error type and additional information, reported as "errorType" and "errorInfo"
+// in the GraphQL response.
+type Error struct {
+	Type    string
+	Message string
+	Info    any
+}
+
+// NewError creates a GraphQL error.
+func NewError(errorType, message string, info any) *Error {
+	return &Error{Type: errorType, Message: message, Info: info}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,56 @@
+package appsync
+
+import "encoding/json"
+
+// Event is the payload sent by AppSync to direct Lambda resolvers. Batch invocations send a list of them.
+type Event struct {
+	Arguments json.RawMessage `json:"arguments"`
+	Source    json.RawMessage `json:"source"`
+	Identity  map[string]any  `json:"identity"`
+	Request   RequestInfo     `json:"request"`
+	Info      Info            `json:"info"`
+	Prev      *PrevResult     `json:"prev"`
+	Stash     map[string]any  `json:"stash"`
+}
+
+// RequestInfo contains the HTTP request data of the GraphQL operation.
+type RequestInfo struct {
+	Headers    map[string]string `json:"headers"`
+	DomainName string            `json:"domainName"`
+}
+
+// Info contains the GraphQL field being resolved.
+type Info struct {
+	FieldName           string         `json:"fieldName"`
+	ParentTypeName      string         `json:"parentTypeName"`
+	Variables           map[string]any `json:"variables"`
+	SelectionSetList    []string       `json:"selectionSetList"`
+	SelectionSetGraphQL string         `json:"selectionSetGraphQL"`
+}
+
+// PrevResult contains the result of the previous function of a pipeline resolver.
+type PrevResult struct {
+	Result json.RawMessage `json:"result"`
+}
+
+// Request is a resolver event with its arguments decoded into "A" and its parent object decoded into "S".
+type Request[A, S any] struct {
+	Arguments A
+	Source    S
+	Event     Event
+}
+
+// Result is the outcome of a single item of a batch resolver. Items with an error are reported as GraphQL errors
+// of their own field, without failing the rest of the batch.
+type Result[R any] struct {
+	Data R
+	Err  error
+}
+
+// batchItem is the shape AppSync expects for each item of a batch response.
+type batchItem struct {
+	Data         any    `json:"data"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	ErrorType    string `json:"errorType,omitempty"`
+	ErrorInfo    any    `json:"errorInfo,omitempty"`
+}
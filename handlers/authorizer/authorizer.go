@@ -0,0 +1,89 @@
+package authorizer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Drafteame/engine"
+)
+
+// TokenFunc validates the bearer token of a TOKEN authorizer request.
+type TokenFunc[C any] func(context.Context, TokenRequest) (Result[C], error)
+
+// RequestFunc validates the identity sources of a REQUEST authorizer request.
+type RequestFunc[C any] func(context.Context, RequestTypeRequest) (Result[C], error)
+
+// SimpleFunc validates the identity sources of an HTTP API authorizer request.
+type SimpleFunc[C any] func(context.Context, SimpleRequest) (SimpleResult[C], error)
+
+// NewTokenHandler creates a TOKEN authorizer handler. Returning ErrUnauthorized from the callback makes API Gateway
+// respond with a 401 status, any other error results in a 500 status.
+func NewTokenHandler[C any](fn TokenFunc[C]) engine.Handler[TokenRequest, Response] {
+	return func(ctx context.Context, evt TokenRequest) (Response, error) {
+		res, err := fn(ctx, evt)
+		if err != nil {
+			return Response{}, authorizationError(err)
+		}
+
+		return buildResponse(res)
+	}
+}
+
+// NewRequestHandler creates a REQUEST authorizer handler. Returning ErrUnauthorized from the callback makes API
+// Gateway respond with a 401 status, any other error results in a 500 status.
+func NewRequestHandler[C any](fn RequestFunc[C]) engine.Handler[RequestTypeRequest, Response] {
+	return func(ctx context.Context, evt RequestTypeRequest) (Response, error) {
+		res, err := fn(ctx, evt)
+		if err != nil {
+			return Response{}, authorizationError(err)
+		}
+
+		return buildResponse(res)
+	}
+}
+
+// NewSimpleHandler creates an HTTP API authorizer handler that uses the simple response format. Returning
+// ErrUnauthorized from the callback is the same as returning a non authorized result.
+func NewSimpleHandler[C any](fn SimpleFunc[C]) engine.Handler[SimpleRequest, SimpleResponse] {
+	return func(ctx context.Context, evt SimpleRequest) (SimpleResponse, error) {
+		res, err := fn(ctx, evt)
+		if errors.Is(err, ErrUnauthorized) {
+			return SimpleResponse{IsAuthorized: false}, nil
+		}
+
+		if err != nil {
+			return SimpleResponse{}, err
+		}
+
+		authCtx, err := encodeContext(res.Context, false)
+		if err != nil {
+			return SimpleResponse{}, err
+		}
+
+		return SimpleResponse{IsAuthorized: res.IsAuthorized, Context: authCtx}, nil
+	}
+}
+
+func buildResponse[C any](res Result[C]) (Response, error) {
+	authCtx, err := encodeContext(res.Context, true)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		PrincipalID:        res.PrincipalID,
+		PolicyDocument:     res.Policy,
+		Context:            authCtx,
+		UsageIdentifierKey: res.UsageIdentifierKey,
+	}, nil
+}
+
+// authorizationError normalizes wrapped ErrUnauthorized errors, since API Gateway only answers with a 401 status
+// when the error message is exactly "Unauthorized".
+func authorizationError(err error) error {
+	if errors.Is(err, ErrUnauthorized) {
+		return ErrUnauthorized
+	}
+
+	return err
+}
@@ -0,0 +1,129 @@
+package authorizer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+const methodARN = "arn:aws:execute-api:us-east-1:123456789012:abcdef123/prod/GET/users/42"
+
+type userContext struct {
+	UserID string `json:"userId"`
+	Admin  bool   `json:"admin,string"`
+}
+
+func TestNewPolicy(t *testing.T) {
+	t.Run("should build allow and deny statements scoped to the stage", func(t *testing.T) {
+		p, err := NewPolicy(methodARN)
+		require.NoError(t, err)
+
+		doc := p.Allow("get", "/users/*").Deny(Wildcard, "admin").Build()
+
+		assert.Equal(t, "2012-10-17", doc.Version)
+		require.Len(t, doc.Statement, 2)
+		assert.Equal(t, "Allow", doc.Statement[0].Effect)
+		assert.Equal(t, []string{"execute-api:Invoke"}, doc.Statement[0].Action)
+		assert.Equal(t, []string{"arn:aws:execute-api:us-east-1:123456789012:abcdef123/prod/GET/users/*"}, doc.Statement[0].Resource)
+		assert.Equal(t, "Deny", doc.Statement[1].Effect)
+		assert.Equal(t, []string{"arn:aws:execute-api:us-east-1:123456789012:abcdef123/prod/*/admin"}, doc.Statement[1].Resource)
+	})
+
+	t.Run("should deny everything when empty", func(t *testing.T) {
+		p, err := NewPolicy(methodARN)
+		require.NoError(t, err)
+
+		doc := p.Build()
+
+		require.Len(t, doc.Statement, 1)
+		assert.Equal(t, "Deny", doc.Statement[0].Effect)
+		assert.Equal(t, []string{"arn:aws:execute-api:us-east-1:123456789012:abcdef123/prod/*/*"}, doc.Statement[0].Resource)
+	})
+
+	t.Run("should fail with invalid arn", func(t *testing.T) {
+		_, err := NewPolicy("arn:aws:s3:::bucket")
+		assert.ErrorIs(t, err, ErrInvalidMethodARN)
+	})
+}
+
+func TestNewTokenHandler(t *testing.T) {
+	t.Run("should return policy and flat context", func(t *testing.T) {
+		handler := NewTokenHandler(func(_ context.Context, req TokenRequest) (Result[userContext], error) {
+			p, err := NewPolicy(req.MethodArn)
+			if err != nil {
+				return Result[userContext]{}, err
+			}
+
+			return Result[userContext]{
+				PrincipalID: "user-42",
+				Policy:      p.AllowAll().Build(),
+				Context:     userContext{UserID: "42", Admin: true},
+			}, nil
+		})
+
+		evt := TokenRequest{Type: "TOKEN", AuthorizationToken: "Bearer abc", MethodArn: methodARN}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-42", res.PrincipalID)
+		assert.Equal(t, map[string]any{"userId": "42", "admin": "true"}, res.Context)
+
+		decoded, err := DecodeContext[userContext](res.Context)
+		require.NoError(t, err)
+		assert.Equal(t, userContext{UserID: "42", Admin: true}, decoded)
+	})
+
+	t.Run("should normalize wrapped unauthorized errors", func(t *testing.T) {
+		handler := NewTokenHandler(func(context.Context, TokenRequest) (Result[userContext], error) {
+			return Result[userContext]{}, fmt.Errorf("expired token: %w", ErrUnauthorized)
+		})
+
+		_, err := testengine.New(context.Background(), TokenRequest{}, handler).Run()
+
+		assert.Equal(t, "Unauthorized", err.Error())
+	})
+
+	t.Run("should reject nested context values", func(t *testing.T) {
+		handler := NewTokenHandler(func(context.Context, TokenRequest) (Result[map[string]any], error) {
+			return Result[map[string]any]{Context: map[string]any{"roles": []string{"admin"}}}, nil
+		})
+
+		_, err := testengine.New(context.Background(), TokenRequest{}, handler).Run()
+
+		assert.ErrorIs(t, err, ErrInvalidContextType)
+	})
+}
+
+func TestNewSimpleHandler(t *testing.T) {
+	t.Run("should return simple response with nested context", func(t *testing.T) {
+		handler := NewSimpleHandler(func(context.Context, SimpleRequest) (SimpleResult[map[string]any], error) {
+			return SimpleResult[map[string]any]{
+				IsAuthorized: true,
+				Context:      map[string]any{"roles": []string{"admin"}},
+			}, nil
+		})
+
+		res, err := testengine.New(context.Background(), SimpleRequest{}, handler).Run()
+
+		require.NoError(t, err)
+		assert.True(t, res.IsAuthorized)
+		assert.Equal(t, []any{"admin"}, res.Context["roles"])
+	})
+
+	t.Run("should not authorize on unauthorized error", func(t *testing.T) {
+		handler := NewSimpleHandler(func(context.Context, SimpleRequest) (SimpleResult[userContext], error) {
+			return SimpleResult[userContext]{}, ErrUnauthorized
+		})
+
+		res, err := testengine.New(context.Background(), SimpleRequest{}, handler).Run()
+
+		require.NoError(t, err)
+		assert.False(t, res.IsAuthorized)
+	})
+}
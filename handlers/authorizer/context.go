@@ -0,0 +1,68 @@
+package authorizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// DecodeContext decodes an authorizer context map, as found in the request context of the integration event, into
+// the type "C". REST APIs forward every context value as a string, so non string fields of "C" should be tagged with
+// the ",string" json option to be decoded on both ends.
+func DecodeContext[C any](m map[string]any) (C, error) {
+	var c C
+
+	if len(m) == 0 {
+		return c, nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return c, errors.Join(err, ErrInvalidContext)
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, errors.Join(err, ErrInvalidContext)
+	}
+
+	return c, nil
+}
+
+// encodeContext converts the typed context into the map expected by API Gateway. When flat is true, only string,
+// number and boolean values are accepted, as required by REST API authorizers.
+func encodeContext[C any](c C, flat bool) (map[string]any, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, errors.Join(err, ErrInvalidContext)
+	}
+
+	if bytes.Equal(b, []byte("null")) {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, errors.Join(err, ErrInvalidContext)
+	}
+
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	if !flat {
+		return m, nil
+	}
+
+	for _, v := range m {
+		switch v.(type) {
+		case string, json.Number, bool:
+		default:
+			return nil, ErrInvalidContextType
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,12 @@
+package authorizer
+
+import "errors"
+
+var (
+	// ErrUnauthorized can be returned by authorization callbacks to make API Gateway respond with a 401 status.
+	ErrUnauthorized = errors.New("Unauthorized") //revive:disable-line:error-strings
+
+	ErrInvalidMethodARN   = errors.New("authorizer: invalid method arn")
+	ErrInvalidContext     = errors.New("authorizer: invalid context")
+	ErrInvalidContextType = errors.New("authorizer: context values must be strings, numbers or booleans")
+)
@@ -0,0 +1,37 @@
+package authorizer
+
+import "github.com/aws/aws-lambda-go/events"
+
+// TokenRequest contains data coming in to a TOKEN authorizer of a REST API.
+type TokenRequest = events.APIGatewayCustomAuthorizerRequest
+
+// RequestTypeRequest contains data coming in to a REQUEST authorizer of a REST API.
+type RequestTypeRequest = events.APIGatewayCustomAuthorizerRequestTypeRequest
+
+// SimpleRequest contains data coming in to an HTTP API authorizer using the 2.0 payload format.
+type SimpleRequest = events.APIGatewayV2CustomAuthorizerV2Request
+
+// Response is the IAM policy response expected by REST API authorizers.
+type Response = events.APIGatewayCustomAuthorizerResponse
+
+// SimpleResponse is the simple response expected by HTTP API authorizers.
+type SimpleResponse = events.APIGatewayV2CustomAuthorizerSimpleResponse
+
+// PolicyDocument is the IAM policy document returned by REST API authorizers.
+type PolicyDocument = events.APIGatewayCustomAuthorizerPolicy
+
+// Result is the outcome of a policy based authorization. "C" is the context type propagated to the integration,
+// it must be a struct or map whose JSON representation is a flat object of string, number or boolean values.
+type Result[C any] struct {
+	PrincipalID        string
+	Policy             PolicyDocument
+	Context            C
+	UsageIdentifierKey string
+}
+
+// SimpleResult is the outcome of an HTTP API simple response authorization. "C" is the context type propagated to
+// the integration.
+type SimpleResult[C any] struct {
+	IsAuthorized bool
+	Context      C
+}
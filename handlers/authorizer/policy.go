@@ -0,0 +1,143 @@
+package authorizer
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	// Wildcard matches any value on a method ARN segment.
+	Wildcard = "*"
+
+	policyVersion = "2012-10-17"
+	invokeAction  = "execute-api:Invoke"
+	effectAllow   = "Allow"
+	effectDeny    = "Deny"
+)
+
+// MethodARN holds the segments of an API Gateway method ARN with the shape
+// arn:{partition}:execute-api:{region}:{accountId}:{apiId}/{stage}/{method}/{resource}.
+type MethodARN struct {
+	Partition string
+	Region    string
+	AccountID string
+	APIID     string
+	Stage     string
+	Method    string
+	Resource  string
+}
+
+// ParseMethodARN splits the given method ARN into its segments.
+func ParseMethodARN(arn string) (MethodARN, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "execute-api" {
+		return MethodARN{}, ErrInvalidMethodARN
+	}
+
+	path := strings.SplitN(parts[5], "/", 4)
+	if len(path) < 3 {
+		return MethodARN{}, ErrInvalidMethodARN
+	}
+
+	m := MethodARN{
+		Partition: parts[1],
+		Region:    parts[3],
+		AccountID: parts[4],
+		APIID:     path[0],
+		Stage:     path[1],
+		Method:    path[2],
+	}
+
+	if len(path) == 4 {
+		m.Resource = path[3]
+	}
+
+	return m, nil
+}
+
+// String builds the method ARN.
+func (m MethodARN) String() string {
+	partition := m.Partition
+	if partition == "" {
+		partition = "aws"
+	}
+
+	return "arn:" + partition + ":execute-api:" + m.Region + ":" + m.AccountID + ":" +
+		m.APIID + "/" + m.Stage + "/" + m.Method + "/" + strings.TrimPrefix(m.Resource, "/")
+}
+
+// Policy builds IAM policy documents for REST API authorizers. Statements are scoped to the API and stage of the
+// method ARN the policy was created from.
+type Policy struct {
+	base  MethodARN
+	allow []string
+	deny  []string
+}
+
+// NewPolicy creates a policy builder scoped to the API and stage of the given method ARN.
+func NewPolicy(methodARN string) (*Policy, error) {
+	base, err := ParseMethodARN(methodARN)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Policy{base: base}, nil
+}
+
+// Allow grants invocation of the given HTTP method and resource path. Both accept Wildcard.
+func (p *Policy) Allow(method, resource string) *Policy {
+	p.allow = append(p.allow, p.resourceARN(method, resource))
+	return p
+}
+
+// Deny rejects invocation of the given HTTP method and resource path. Both accept Wildcard.
+func (p *Policy) Deny(method, resource string) *Policy {
+	p.deny = append(p.deny, p.resourceARN(method, resource))
+	return p
+}
+
+// AllowAll grants invocation of every method and resource on the stage.
+func (p *Policy) AllowAll() *Policy {
+	return p.Allow(Wildcard, Wildcard)
+}
+
+// DenyAll rejects invocation of every method and resource on the stage.
+func (p *Policy) DenyAll() *Policy {
+	return p.Deny(Wildcard, Wildcard)
+}
+
+// Build returns the policy document. A policy without statements denies everything.
+func (p *Policy) Build() PolicyDocument {
+	doc := PolicyDocument{Version: policyVersion}
+
+	if len(p.allow) > 0 {
+		doc.Statement = append(doc.Statement, statement(effectAllow, p.allow))
+	}
+
+	if len(p.deny) > 0 {
+		doc.Statement = append(doc.Statement, statement(effectDeny, p.deny))
+	}
+
+	if len(doc.Statement) == 0 {
+		doc.Statement = append(doc.Statement, statement(effectDeny, []string{p.resourceARN(Wildcard, Wildcard)}))
+	}
+
+	return doc
+}
+
+func (p *Policy) resourceARN(method, resource string) string {
+	m := p.base
+	m.Method = strings.ToUpper(method)
+	m.Resource = resource
+
+	return m.String()
+}
+
+func statement(effect string, resources []string) events.IAMPolicyStatement {
+	return events.IAMPolicyStatement{
+		Action:   []string{invokeAction},
+		Effect:   effect,
+		Resource: resources,
+	}
+}
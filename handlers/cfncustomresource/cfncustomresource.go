@@ -0,0 +1,350 @@
+package cfncustomresource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+
+	"github.com/Drafteame/engine"
+)
+
+// maxReasonLength is the maximum size of the reason accepted by CloudFormation.
+const maxReasonLength = 4096
+
+// defaultTimeoutMargin is the margin used when Config.TimeoutMargin is not set.
+const defaultTimeoutMargin = 5 * time.Second
+
+// defaultSendBackoff is the delay before retrying to send the response when Config.SendBackoff is not set.
+const defaultSendBackoff = 100 * time.Millisecond
+
+// HTTPClient sends the response to the pre-signed URL of the event. It is satisfied by *http.Client.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Config is the configuration for the custom resource handler.
+type Config struct {
+	// Client sends the response to CloudFormation. Defaults to http.DefaultClient.
+	Client HTTPClient
+
+	// TimeoutMargin is how long before the Lambda deadline a FAILED response is sent if the operation has not
+	// finished yet. The context given to the operation expires at the same moment. Defaults to 5s.
+	TimeoutMargin time.Duration
+
+	// SendAttempts is how many times sending the response is tried before giving up. The time reserved by
+	// TimeoutMargin is split between the attempts, so a hanging attempt does not consume the whole margin.
+	SendAttempts int
+
+	// SendBackoff is the delay before the second attempt to send the response. It doubles on every later attempt.
+	SendBackoff time.Duration
+}
+
+// DefaultConfig returns the default configuration for the custom resource handler.
+func DefaultConfig() Config {
+	return Config{
+		Client:        http.DefaultClient,
+		TimeoutMargin: defaultTimeoutMargin,
+		SendAttempts:  3,
+		SendBackoff:   defaultSendBackoff,
+	}
+}
+
+// NewHandler creates a custom resource handler with the default configuration.
+func NewHandler[P, D any](resource Resource[P, D]) engine.Handler[Event, Response] {
+	return NewHandlerWithConfig(resource, DefaultConfig())
+}
+
+// NewHandlerWithConfig creates a custom resource handler that dispatches the event to the matching operation and
+// always sends a response to CloudFormation, even when the operation fails, panics or is about to exceed the Lambda
+// deadline. The handler only returns an error when the response could not be sent.
+func NewHandlerWithConfig[P, D any](resource Resource[P, D], config Config) engine.Handler[Event, Response] {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+
+	if config.TimeoutMargin <= 0 {
+		config.TimeoutMargin = defaultTimeoutMargin
+	}
+
+	if config.SendAttempts <= 0 {
+		config.SendAttempts = 1
+	}
+
+	if config.SendBackoff <= 0 {
+		config.SendBackoff = defaultSendBackoff
+	}
+
+	return func(ctx context.Context, evt Event) (Response, error) {
+		res := run(ctx, resource, evt, config.TimeoutMargin)
+
+		if err := send(ctx, config, evt.ResponseURL, res); err != nil {
+			return res, err
+		}
+
+		return res, nil
+	}
+}
+
+type outcome[D any] struct {
+	result Result[D]
+	err    error
+}
+
+func run[P, D any](ctx context.Context, resource Resource[P, D], evt Event, margin time.Duration) Response {
+	res := Response{
+		RequestID:          evt.RequestID,
+		LogicalResourceID:  evt.LogicalResourceID,
+		StackID:            evt.StackID,
+		PhysicalResourceID: evt.PhysicalResourceID,
+	}
+
+	if res.PhysicalResourceID == "" {
+		res.PhysicalResourceID = evt.LogicalResourceID + "-" + evt.RequestID
+	}
+
+	opCtx, cancel := operationContext(ctx, margin)
+	defer cancel()
+
+	done := make(chan outcome[D], 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome[D]{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+
+		result, err := dispatch(opCtx, resource, evt)
+		done <- outcome[D]{result: result, err: err}
+	}()
+
+	var out outcome[D]
+
+	select {
+	case out = <-done:
+	case <-opCtx.Done():
+		out = outcome[D]{err: errors.Join(opCtx.Err(), ErrTimeout)}
+	}
+
+	if out.err != nil {
+		return fail(res, out.err)
+	}
+
+	if out.result.PhysicalResourceID != "" {
+		res.PhysicalResourceID = out.result.PhysicalResourceID
+	}
+
+	data, err := encodeData(out.result.Data)
+	if err != nil {
+		return fail(res, err)
+	}
+
+	res.Status = StatusSuccess
+	res.Data = data
+	res.NoEcho = out.result.NoEcho
+
+	return res
+}
+
+func dispatch[P, D any](ctx context.Context, resource Resource[P, D], evt Event) (Result[D], error) {
+	req := Request[P]{Event: evt}
+
+	if err := decodeProperties(evt.ResourceProperties, &req.Properties); err != nil {
+		return Result[D]{}, err
+	}
+
+	if err := decodeProperties(evt.OldResourceProperties, &req.OldProperties); err != nil {
+		return Result[D]{}, err
+	}
+
+	var op Operation[P, D]
+
+	switch evt.RequestType {
+	case RequestCreate:
+		op = resource.Create
+	case RequestUpdate:
+		op = resource.Update
+	case RequestDelete:
+		op = resource.Delete
+	default:
+		return Result[D]{}, ErrUnknownRequestType
+	}
+
+	if op == nil {
+		return Result[D]{}, nil
+	}
+
+	return op(ctx, req)
+}
+
+// operationContext derives the context given to operations, which expires TimeoutMargin before the Lambda deadline
+// to leave time for sending the response.
+func operationContext(ctx context.Context, margin time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, deadline.Add(-margin))
+}
+
+func fail(res Response, err error) Response {
+	reason := err.Error()
+
+	if lambdacontext.LogStreamName != "" {
+		reason += " (see log stream " + lambdacontext.LogStreamName + ")"
+	}
+
+	if len(reason) > maxReasonLength {
+		reason = reason[:maxReasonLength]
+	}
+
+	res.Status = StatusFailed
+	res.Reason = reason
+	res.Data = nil
+	res.NoEcho = false
+
+	return res
+}
+
+func decodeProperties(props map[string]any, v any) error {
+	if len(props) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(props)
+	if err != nil {
+		return errors.Join(err, ErrInvalidProperties)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return errors.Join(err, ErrInvalidProperties)
+	}
+
+	return nil
+}
+
+func encodeData(data any) (map[string]any, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Join(err, ErrInvalidData)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Join(err, ErrInvalidData)
+	}
+
+	return m, nil
+}
+
+// send puts the response to the pre-signed URL. It does not use the invocation context, so the response is still
+// sent when the operation exhausted it, but it never outlives the Lambda deadline.
+func send(ctx context.Context, config Config, url string, res Response) error {
+	body, err := json.Marshal(res)
+	if err != nil {
+		return errors.Join(err, ErrSendingResponse)
+	}
+
+	ctx, cancel := sendContext(ctx, config.TimeoutMargin)
+	defer cancel()
+
+	var errs error
+
+	backoff := config.SendBackoff
+
+	for attempt := 0; attempt < config.SendAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return errors.Join(errs, ctx.Err(), ErrSendingResponse)
+			case <-timer.C:
+			}
+
+			backoff *= 2
+		}
+
+		attemptCtx, cancelAttempt := attemptContext(ctx, config, config.SendAttempts-attempt)
+		err := put(attemptCtx, config.Client, url, body)
+		cancelAttempt()
+
+		if err == nil {
+			return nil
+		}
+
+		errs = errors.Join(errs, err)
+	}
+
+	return errors.Join(errs, ErrSendingResponse)
+}
+
+// sendContext detaches the invocation context from its cancellation and bounds it by the Lambda deadline, or by
+// TimeoutMargin when the invocation has no deadline.
+func sendContext(ctx context.Context, margin time.Duration) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+
+	if margin > 0 {
+		return context.WithTimeout(detached, margin)
+	}
+
+	return context.WithCancel(detached)
+}
+
+// attemptContext gives a single attempt its share of TimeoutMargin, never more than an even split of the time left
+// between the remaining attempts.
+func attemptContext(ctx context.Context, config Config, remaining int) (context.Context, context.CancelFunc) {
+	timeout := config.TimeoutMargin / time.Duration(config.SendAttempts)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		left := time.Until(deadline) / time.Duration(remaining)
+		if timeout <= 0 || left < timeout {
+			timeout = left
+		}
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func put(ctx context.Context, client HTTPClient, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	// pre-signed S3 URLs are signed without content type
+	req.Header.Set("Content-Type", "")
+	req.ContentLength = int64(len(body))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package cfncustomresource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type bucketProps struct {
+	Name     string `json:"Name"`
+	Replicas int    `json:"Replicas,string"`
+}
+
+type bucketData struct {
+	Arn string `json:"Arn"`
+}
+
+// responseServer stands in for the pre-signed S3 URL and captures the responses sent to it.
+func responseServer(t *testing.T) (*httptest.Server, <-chan map[string]any) {
+	t.Helper()
+
+	sent := make(chan map[string]any, 4)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Empty(t, r.Header.Get("Content-Type"))
+
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		sent <- body
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv, sent
+}
+
+func event(url string, reqType cfn.RequestType) Event {
+	return Event{
+		RequestType:        reqType,
+		RequestID:          "req-1",
+		ResponseURL:        url,
+		LogicalResourceID:  "Bucket",
+		StackID:            "arn:aws:cloudformation:us-east-1:123456789012:stack/test/1",
+		ResourceProperties: map[string]any{"ServiceToken": "arn", "Name": "logs", "Replicas": "2"},
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should send success with data and physical id", func(t *testing.T) {
+		srv, sent := responseServer(t)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{
+			Create: func(_ context.Context, req Request[bucketProps]) (Result[bucketData], error) {
+				assert.Equal(t, bucketProps{Name: "logs", Replicas: 2}, req.Properties)
+
+				return Result[bucketData]{
+					PhysicalResourceID: req.Properties.Name,
+					Data:               bucketData{Arn: "arn:aws:s3:::logs"},
+					NoEcho:             true,
+				}, nil
+			},
+		}, Config{Client: srv.Client()})
+
+		res, err := testengine.New(context.Background(), event(srv.URL, RequestCreate), handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusSuccess, res.Status)
+
+		body := <-sent
+		assert.Equal(t, "SUCCESS", body["Status"])
+		assert.Equal(t, "logs", body["PhysicalResourceId"])
+		assert.Equal(t, "req-1", body["RequestId"])
+		assert.Equal(t, true, body["NoEcho"])
+		assert.Equal(t, map[string]any{"Arn": "arn:aws:s3:::logs"}, body["Data"])
+	})
+
+	t.Run("should keep the physical id on delete", func(t *testing.T) {
+		srv, sent := responseServer(t)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{}, Config{Client: srv.Client()})
+
+		evt := event(srv.URL, RequestDelete)
+		evt.PhysicalResourceID = "logs"
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+
+		body := <-sent
+		assert.Equal(t, "SUCCESS", body["Status"])
+		assert.Equal(t, "logs", body["PhysicalResourceId"])
+	})
+
+	t.Run("should send failure on error", func(t *testing.T) {
+		srv, sent := responseServer(t)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{
+			Update: func(context.Context, Request[bucketProps]) (Result[bucketData], error) {
+				return Result[bucketData]{}, errors.New("bucket name taken")
+			},
+		}, Config{Client: srv.Client()})
+
+		_, err := testengine.New(context.Background(), event(srv.URL, RequestUpdate), handler).Run()
+
+		require.NoError(t, err)
+
+		body := <-sent
+		assert.Equal(t, "FAILED", body["Status"])
+		assert.Equal(t, "bucket name taken", body["Reason"])
+		assert.Equal(t, "Bucket-req-1", body["PhysicalResourceId"])
+	})
+
+	t.Run("should send failure on panic", func(t *testing.T) {
+		srv, sent := responseServer(t)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{
+			Create: func(context.Context, Request[bucketProps]) (Result[bucketData], error) {
+				panic("boom")
+			},
+		}, Config{Client: srv.Client()})
+
+		_, err := testengine.New(context.Background(), event(srv.URL, RequestCreate), handler).Run()
+
+		require.NoError(t, err)
+
+		body := <-sent
+		assert.Equal(t, "FAILED", body["Status"])
+		assert.Equal(t, "panic: boom", body["Reason"])
+	})
+
+	t.Run("should send failure before the deadline", func(t *testing.T) {
+		srv, sent := responseServer(t)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{
+			Create: func(ctx context.Context, _ Request[bucketProps]) (Result[bucketData], error) {
+				<-make(chan struct{})
+				return Result[bucketData]{}, ctx.Err()
+			},
+		}, Config{Client: srv.Client(), TimeoutMargin: 450 * time.Millisecond})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err := testengine.New(ctx, event(srv.URL, RequestCreate), handler).Run()
+
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 450*time.Millisecond)
+
+		body := <-sent
+		assert.Equal(t, "FAILED", body["Status"])
+		assert.Contains(t, body["Reason"], "operation timed out")
+	})
+
+	t.Run("should default the timeout margin", func(t *testing.T) {
+		srv, sent := responseServer(t)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{
+			Create: func(ctx context.Context, _ Request[bucketProps]) (Result[bucketData], error) {
+				<-ctx.Done()
+				return Result[bucketData]{}, ctx.Err()
+			},
+		}, Config{Client: srv.Client()})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		_, err := testengine.New(ctx, event(srv.URL, RequestCreate), handler).Run()
+
+		require.NoError(t, err)
+
+		body := <-sent
+		assert.Equal(t, "FAILED", body["Status"])
+		assert.Contains(t, body["Reason"], "operation timed out")
+	})
+
+	t.Run("should return error when the response cannot be sent", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{}, Config{Client: srv.Client(), SendAttempts: 2})
+
+		_, err := testengine.New(context.Background(), event(srv.URL, RequestCreate), handler).Run()
+
+		assert.ErrorIs(t, err, ErrSendingResponse)
+	})
+
+	t.Run("should give up sending when the response server hangs", func(t *testing.T) {
+		var attempts atomic.Int32
+
+		release := make(chan struct{})
+
+		srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			attempts.Add(1)
+			<-release
+		}))
+		defer srv.Close()
+		defer close(release)
+
+		handler := NewHandlerWithConfig(Resource[bucketProps, bucketData]{}, Config{
+			Client:        srv.Client(),
+			TimeoutMargin: 300 * time.Millisecond,
+			SendAttempts:  3,
+			SendBackoff:   10 * time.Millisecond,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err := testengine.New(ctx, event(srv.URL, RequestCreate), handler).Run()
+
+		assert.ErrorIs(t, err, ErrSendingResponse)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+}
@@ -0,0 +1,11 @@
+package cfncustomresource
+
+import "errors"
+
+var (
+	ErrUnknownRequestType = errors.New("cfncustomresource: unknown request type")
+	ErrInvalidProperties  = errors.New("cfncustomresource: invalid resource properties")
+	ErrInvalidData        = errors.New("cfncustomresource: invalid response data")
+	ErrTimeout            = errors.New("cfncustomresource: operation timed out")
+	ErrSendingResponse    = errors.New("cfncustomresource: sending response failed")
+)
@@ -0,0 +1,55 @@
+package cfncustomresource
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/cfn"
+)
+
+// Event is the custom resource request sent by CloudFormation.
+type Event = cfn.Event
+
+// Response is the custom resource response sent back to CloudFormation.
+type Response = cfn.Response
+
+// Request types sent on the "RequestType" field of the event.
+const (
+	RequestCreate = cfn.RequestCreate
+	RequestUpdate = cfn.RequestUpdate
+	RequestDelete = cfn.RequestDelete
+)
+
+// Response statuses.
+const (
+	StatusSuccess = cfn.StatusSuccess
+	StatusFailed  = cfn.StatusFailed
+)
+
+// Request is the custom resource event with its resource properties decoded into "P". CloudFormation sends every
+// scalar property as a string, so non string fields of "P" should be tagged with the ",string" json option.
+type Request[P any] struct {
+	Event
+	Properties    P
+	OldProperties P
+}
+
+// Result is the outcome of a custom resource operation. "D" is the type of the attributes exposed through
+// Fn::GetAtt, it must encode as a JSON object.
+type Result[D any] struct {
+	// PhysicalResourceID identifies the resource. When empty, the physical id of the event is kept, or one is
+	// generated on creation.
+	PhysicalResourceID string
+	Data               D
+	// NoEcho masks the data when it is retrieved with Fn::GetAtt.
+	NoEcho bool
+}
+
+// Operation handles a single custom resource request type.
+type Operation[P, D any] func(ctx context.Context, req Request[P]) (Result[D], error)
+
+// Resource groups the operations of a custom resource. Missing operations succeed without doing anything.
+type Resource[P, D any] struct {
+	Create Operation[P, D]
+	Update Operation[P, D]
+	Delete Operation[P, D]
+}
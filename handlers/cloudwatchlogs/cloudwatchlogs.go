@@ -0,0 +1,76 @@
+package cloudwatchlogs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Drafteame/engine"
+)
+
+// ErrDecodingData is returned when the subscription payload cannot be decoded.
+var ErrDecodingData = errors.New("cloudwatchlogs: decoding data failed")
+
+// LogEventFunc processes a single log event.
+type LogEventFunc[M any] func(context.Context, LogEvent[M]) error
+
+// NewHandler creates a CloudWatch Logs subscription handler that base64 decodes and gunzips the payload, skips
+// control messages and calls fn for every log event in order. Processing stops at the first failing log event.
+// Messages that are JSON objects are decoded into "M"; use map[string]any to keep them untyped.
+func NewHandler[M any](fn LogEventFunc[M]) engine.Handler[Event, struct{}] {
+	return func(ctx context.Context, evt Event) (struct{}, error) {
+		data, err := Decode(evt)
+		if err != nil {
+			return struct{}{}, err
+		}
+
+		if data.MessageType == MessageTypeControl {
+			return struct{}{}, nil
+		}
+
+		for _, le := range data.LogEvents {
+			logEvent := LogEvent[M]{
+				ID:                  le.ID,
+				Timestamp:           time.UnixMilli(le.Timestamp),
+				Message:             le.Message,
+				Owner:               data.Owner,
+				LogGroup:            data.LogGroup,
+				LogStream:           data.LogStream,
+				SubscriptionFilters: data.SubscriptionFilters,
+				Fields:              parseFields[M](le.Message),
+			}
+
+			if err := fn(ctx, logEvent); err != nil {
+				return struct{}{}, err
+			}
+		}
+
+		return struct{}{}, nil
+	}
+}
+
+// Decode base64 decodes and gunzips the payload of the event.
+func Decode(evt Event) (Data, error) {
+	data, err := evt.AWSLogs.Parse()
+	if err != nil {
+		return Data{}, errors.Join(err, ErrDecodingData)
+	}
+
+	return data, nil
+}
+
+func parseFields[M any](msg string) *M {
+	msg = strings.TrimSpace(msg)
+	if !strings.HasPrefix(msg, "{") {
+		return nil
+	}
+
+	fields := new(M)
+	if err := json.Unmarshal([]byte(msg), fields); err != nil {
+		return nil
+	}
+
+	return fields
+}
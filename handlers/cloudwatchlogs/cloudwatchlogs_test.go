@@ -0,0 +1,114 @@
+package cloudwatchlogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type appLog struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+func encode(t *testing.T, data map[string]any) Event {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	require.NoError(t, json.NewEncoder(zw).Encode(data))
+	require.NoError(t, zw.Close())
+
+	var evt Event
+	evt.AWSLogs.Data = base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	return evt
+}
+
+func dataMessage() map[string]any {
+	return map[string]any{
+		"messageType":         "DATA_MESSAGE",
+		"owner":               "123456789012",
+		"logGroup":            "/aws/lambda/orders",
+		"logStream":           "2024/01/01/[$LATEST]abc",
+		"subscriptionFilters": []string{"shipper"},
+		"logEvents": []map[string]any{
+			{"id": "1", "timestamp": 1700000000000, "message": `{"level":"INFO","msg":"order created"}`},
+			{"id": "2", "timestamp": 1700000001000, "message": "START RequestId: abc"},
+		},
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should decode log events and json messages", func(t *testing.T) {
+		var got []LogEvent[appLog]
+
+		handler := NewHandler(func(_ context.Context, le LogEvent[appLog]) error {
+			got = append(got, le)
+			return nil
+		})
+
+		_, err := testengine.New(context.Background(), encode(t, dataMessage()), handler).Run()
+
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "/aws/lambda/orders", got[0].LogGroup)
+		assert.Equal(t, []string{"shipper"}, got[0].SubscriptionFilters)
+		assert.Equal(t, int64(1700000000), got[0].Timestamp.Unix())
+		assert.Equal(t, &appLog{Level: "INFO", Msg: "order created"}, got[0].Fields)
+		assert.Nil(t, got[1].Fields)
+		assert.Equal(t, "START RequestId: abc", got[1].Message)
+	})
+
+	t.Run("should skip control messages", func(t *testing.T) {
+		calls := 0
+
+		handler := NewHandler(func(context.Context, LogEvent[map[string]any]) error {
+			calls++
+			return nil
+		})
+
+		msg := dataMessage()
+		msg["messageType"] = "CONTROL_MESSAGE"
+
+		_, err := testengine.New(context.Background(), encode(t, msg), handler).Run()
+
+		require.NoError(t, err)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("should stop at first error", func(t *testing.T) {
+		calls := 0
+
+		handler := NewHandler(func(context.Context, LogEvent[map[string]any]) error {
+			calls++
+			return errors.New("shipping failed")
+		})
+
+		_, err := testengine.New(context.Background(), encode(t, dataMessage()), handler).Run()
+
+		assert.EqualError(t, err, "shipping failed")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should fail on invalid data", func(t *testing.T) {
+		handler := NewHandler(func(context.Context, LogEvent[map[string]any]) error { return nil })
+
+		var evt Event
+		evt.AWSLogs.Data = "not-gzip"
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		assert.ErrorIs(t, err, ErrDecodingData)
+	})
+}
@@ -0,0 +1,33 @@
+package cloudwatchlogs
+
+import (
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Event is the payload sent by a CloudWatch Logs subscription filter.
+type Event = events.CloudwatchLogsEvent
+
+// Data is the decoded payload of a subscription event.
+type Data = events.CloudwatchLogsData
+
+// Message types sent on the "messageType" field of the decoded payload.
+const (
+	MessageTypeData    = "DATA_MESSAGE"
+	MessageTypeControl = "CONTROL_MESSAGE"
+)
+
+// LogEvent is a single log event of a subscription, along with the log group and stream it was written to.
+type LogEvent[M any] struct {
+	ID                  string
+	Timestamp           time.Time
+	Message             string
+	Owner               string
+	LogGroup            string
+	LogStream           string
+	SubscriptionFilters []string
+
+	// Fields is the message decoded into "M". It is nil when the message is not a JSON object or does not match "M".
+	Fields *M
+}
@@ -0,0 +1,166 @@
+package cognito
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Drafteame/engine"
+)
+
+// TriggerFunc handles the request portion of a Cognito User Pool trigger and returns the response portion. The
+// handlers built from it take care of echoing back the whole event with the response set, as Cognito expects.
+type TriggerFunc[Req, Res any] func(ctx context.Context, header Header, req Req) (Res, error)
+
+// NewPreSignUpHandler creates a handler for the pre sign-up trigger.
+func NewPreSignUpHandler(fn TriggerFunc[PreSignUpRequest, PreSignUpResponse]) engine.Handler[PreSignUpEvent, PreSignUpEvent] {
+	return func(ctx context.Context, evt PreSignUpEvent) (PreSignUpEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return PreSignUpEvent{}, err
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewPostConfirmationHandler creates a handler for the post confirmation trigger.
+func NewPostConfirmationHandler(fn func(context.Context, Header, PostConfirmationRequest) error) engine.Handler[PostConfirmationEvent, PostConfirmationEvent] {
+	return func(ctx context.Context, evt PostConfirmationEvent) (PostConfirmationEvent, error) {
+		if err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request); err != nil {
+			return PostConfirmationEvent{}, err
+		}
+
+		return evt, nil
+	}
+}
+
+// NewPreTokenGenHandler creates a handler for the version 1 pre token generation trigger.
+func NewPreTokenGenHandler(fn TriggerFunc[PreTokenGenRequest, PreTokenGenResponse]) engine.Handler[PreTokenGenEvent, PreTokenGenEvent] {
+	return func(ctx context.Context, evt PreTokenGenEvent) (PreTokenGenEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return PreTokenGenEvent{}, err
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewPreTokenGenV2Handler creates a handler for the version 2 pre token generation trigger, which can also customize
+// the access token.
+func NewPreTokenGenV2Handler(fn TriggerFunc[PreTokenGenV2Request, PreTokenGenV2Response]) engine.Handler[PreTokenGenV2Event, PreTokenGenV2Event] {
+	return func(ctx context.Context, evt PreTokenGenV2Event) (PreTokenGenV2Event, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return PreTokenGenV2Event{}, err
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewCustomMessageHandler creates a handler for the custom message trigger. Custom messages are rejected when they
+// do not include the code parameter, or the username parameter for users created by an administrator, since Cognito
+// would fail to deliver them.
+func NewCustomMessageHandler(fn TriggerFunc[CustomMessageRequest, CustomMessageResponse]) engine.Handler[CustomMessageEvent, CustomMessageEvent] {
+	return func(ctx context.Context, evt CustomMessageEvent) (CustomMessageEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return CustomMessageEvent{}, err
+		}
+
+		if err := validateCustomMessage(evt.TriggerSource, evt.Request, res); err != nil {
+			return CustomMessageEvent{}, err
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewDefineAuthChallengeHandler creates a handler for the define auth challenge trigger.
+func NewDefineAuthChallengeHandler(fn TriggerFunc[DefineAuthChallengeRequest, DefineAuthChallengeResponse]) engine.Handler[DefineAuthChallengeEvent, DefineAuthChallengeEvent] {
+	return func(ctx context.Context, evt DefineAuthChallengeEvent) (DefineAuthChallengeEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return DefineAuthChallengeEvent{}, err
+		}
+
+		if res.IssueTokens && res.FailAuthentication {
+			return DefineAuthChallengeEvent{}, ErrConflictingChallenge
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewCreateAuthChallengeHandler creates a handler for the create auth challenge trigger.
+func NewCreateAuthChallengeHandler(fn TriggerFunc[CreateAuthChallengeRequest, CreateAuthChallengeResponse]) engine.Handler[CreateAuthChallengeEvent, CreateAuthChallengeEvent] {
+	return func(ctx context.Context, evt CreateAuthChallengeEvent) (CreateAuthChallengeEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return CreateAuthChallengeEvent{}, err
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewVerifyAuthChallengeHandler creates a handler for the verify auth challenge response trigger.
+func NewVerifyAuthChallengeHandler(fn TriggerFunc[VerifyAuthChallengeRequest, VerifyAuthChallengeResponse]) engine.Handler[VerifyAuthChallengeEvent, VerifyAuthChallengeEvent] {
+	return func(ctx context.Context, evt VerifyAuthChallengeEvent) (VerifyAuthChallengeEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.Request)
+		if err != nil {
+			return VerifyAuthChallengeEvent{}, err
+		}
+
+		evt.Response = res
+
+		return evt, nil
+	}
+}
+
+// NewUserMigrationHandler creates a handler for the user migration trigger. Returning an error tells Cognito the user
+// could not be migrated.
+func NewUserMigrationHandler(fn TriggerFunc[UserMigrationRequest, UserMigrationResponse]) engine.Handler[UserMigrationEvent, UserMigrationEvent] {
+	return func(ctx context.Context, evt UserMigrationEvent) (UserMigrationEvent, error) {
+		res, err := fn(ctx, evt.CognitoEventUserPoolsHeader, evt.CognitoEventUserPoolsMigrateUserRequest)
+		if err != nil {
+			return UserMigrationEvent{}, err
+		}
+
+		evt.CognitoEventUserPoolsMigrateUserResponse = res
+
+		return evt, nil
+	}
+}
+
+func validateCustomMessage(source string, req CustomMessageRequest, res CustomMessageResponse) error {
+	for _, msg := range []string{res.EmailMessage, res.SMSMessage} {
+		if msg == "" {
+			continue
+		}
+
+		if req.CodeParameter != "" && !strings.Contains(msg, req.CodeParameter) {
+			return ErrMissingCodeParameter
+		}
+
+		if source == TriggerCustomMessageAdminCreateUser && req.UsernameParameter != "" &&
+			!strings.Contains(msg, req.UsernameParameter) {
+			return ErrMissingUsernameParameter
+		}
+	}
+
+	return nil
+}
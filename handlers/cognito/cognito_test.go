@@ -0,0 +1,132 @@
+package cognito
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+func TestNewPreSignUpHandler(t *testing.T) {
+	t.Run("should echo the event with the response set", func(t *testing.T) {
+		handler := NewPreSignUpHandler(func(_ context.Context, h Header, req PreSignUpRequest) (PreSignUpResponse, error) {
+			assert.Equal(t, TriggerPreSignUpSignUp, h.TriggerSource)
+			return PreSignUpResponse{AutoConfirmUser: req.UserAttributes["email"] == "a@example.com"}, nil
+		})
+
+		var evt PreSignUpEvent
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"version": "1",
+			"triggerSource": "PreSignUp_SignUp",
+			"userPoolId": "us-east-1_abc",
+			"userName": "john",
+			"request": {"userAttributes": {"email": "a@example.com"}},
+			"response": {}
+		}`), &evt))
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, "john", res.UserName)
+		assert.Equal(t, "us-east-1_abc", res.UserPoolID)
+		assert.Equal(t, evt.Request, res.Request)
+		assert.True(t, res.Response.AutoConfirmUser)
+	})
+
+	t.Run("should return callback errors", func(t *testing.T) {
+		handler := NewPreSignUpHandler(func(context.Context, Header, PreSignUpRequest) (PreSignUpResponse, error) {
+			return PreSignUpResponse{}, errors.New("domain not allowed")
+		})
+
+		_, err := testengine.New(context.Background(), PreSignUpEvent{}, handler).Run()
+
+		assert.EqualError(t, err, "domain not allowed")
+	})
+}
+
+func TestNewCustomMessageHandler(t *testing.T) {
+	evt := CustomMessageEvent{
+		CognitoEventUserPoolsHeader: Header{TriggerSource: TriggerCustomMessageAdminCreateUser},
+		Request: CustomMessageRequest{
+			CodeParameter:     "{####}",
+			UsernameParameter: "{username}",
+		},
+	}
+
+	t.Run("should set the custom message", func(t *testing.T) {
+		handler := NewCustomMessageHandler(func(context.Context, Header, CustomMessageRequest) (CustomMessageResponse, error) {
+			return CustomMessageResponse{EmailSubject: "Welcome", EmailMessage: "User {username}, code {####}"}, nil
+		})
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, "Welcome", res.Response.EmailSubject)
+	})
+
+	t.Run("should reject messages without code parameter", func(t *testing.T) {
+		handler := NewCustomMessageHandler(func(context.Context, Header, CustomMessageRequest) (CustomMessageResponse, error) {
+			return CustomMessageResponse{SMSMessage: "Your user is {username}"}, nil
+		})
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		assert.ErrorIs(t, err, ErrMissingCodeParameter)
+	})
+
+	t.Run("should reject messages without username parameter", func(t *testing.T) {
+		handler := NewCustomMessageHandler(func(context.Context, Header, CustomMessageRequest) (CustomMessageResponse, error) {
+			return CustomMessageResponse{EmailMessage: "Your code is {####}"}, nil
+		})
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		assert.ErrorIs(t, err, ErrMissingUsernameParameter)
+	})
+}
+
+func TestNewDefineAuthChallengeHandler(t *testing.T) {
+	t.Run("should reject conflicting responses", func(t *testing.T) {
+		handler := NewDefineAuthChallengeHandler(func(context.Context, Header, DefineAuthChallengeRequest) (DefineAuthChallengeResponse, error) {
+			return DefineAuthChallengeResponse{IssueTokens: true, FailAuthentication: true}, nil
+		})
+
+		_, err := testengine.New(context.Background(), DefineAuthChallengeEvent{}, handler).Run()
+
+		assert.ErrorIs(t, err, ErrConflictingChallenge)
+	})
+}
+
+func TestNewUserMigrationHandler(t *testing.T) {
+	t.Run("should marshal the migrated user in the response", func(t *testing.T) {
+		handler := NewUserMigrationHandler(func(_ context.Context, _ Header, req UserMigrationRequest) (UserMigrationResponse, error) {
+			assert.Equal(t, "secret", req.Password)
+
+			return UserMigrationResponse{
+				UserAttributes:  map[string]string{"email": "a@example.com"},
+				FinalUserStatus: FinalUserStatusConfirmed,
+				MessageAction:   MessageActionSuppress,
+			}, nil
+		})
+
+		evt := UserMigrationEvent{CognitoEventUserPoolsMigrateUserRequest: UserMigrationRequest{Password: "secret"}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+		require.NoError(t, err)
+
+		b, err := json.Marshal(res)
+		require.NoError(t, err)
+
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(b, &out))
+
+		response := out["response"].(map[string]any)
+		assert.Equal(t, "CONFIRMED", response["finalUserStatus"])
+		assert.Equal(t, "SUPPRESS", response["messageAction"])
+	})
+}
@@ -0,0 +1,9 @@
+package cognito
+
+import "errors"
+
+var (
+	ErrMissingCodeParameter     = errors.New("cognito: custom message must include the code parameter")
+	ErrMissingUsernameParameter = errors.New("cognito: custom message must include the username parameter")
+	ErrConflictingChallenge     = errors.New("cognito: cannot issue tokens and fail authentication at the same time")
+)
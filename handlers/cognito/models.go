@@ -0,0 +1,111 @@
+package cognito
+
+import "github.com/aws/aws-lambda-go/events"
+
+// Header contains the data shared by every Cognito User Pool trigger event.
+type Header = events.CognitoEventUserPoolsHeader
+
+// Pre sign-up trigger.
+type (
+	PreSignUpEvent    = events.CognitoEventUserPoolsPreSignup
+	PreSignUpRequest  = events.CognitoEventUserPoolsPreSignupRequest
+	PreSignUpResponse = events.CognitoEventUserPoolsPreSignupResponse
+)
+
+// Post confirmation trigger.
+type (
+	PostConfirmationEvent    = events.CognitoEventUserPoolsPostConfirmation
+	PostConfirmationRequest  = events.CognitoEventUserPoolsPostConfirmationRequest
+	PostConfirmationResponse = events.CognitoEventUserPoolsPostConfirmationResponse
+)
+
+// Pre token generation trigger, version 1.
+type (
+	PreTokenGenEvent    = events.CognitoEventUserPoolsPreTokenGen
+	PreTokenGenRequest  = events.CognitoEventUserPoolsPreTokenGenRequest
+	PreTokenGenResponse = events.CognitoEventUserPoolsPreTokenGenResponse
+)
+
+// Pre token generation trigger, version 2.
+type (
+	PreTokenGenV2Event    = events.CognitoEventUserPoolsPreTokenGenV2
+	PreTokenGenV2Request  = events.CognitoEventUserPoolsPreTokenGenV2Request
+	PreTokenGenV2Response = events.CognitoEventUserPoolsPreTokenGenV2Response
+)
+
+// Custom message trigger.
+type (
+	CustomMessageEvent    = events.CognitoEventUserPoolsCustomMessage
+	CustomMessageRequest  = events.CognitoEventUserPoolsCustomMessageRequest
+	CustomMessageResponse = events.CognitoEventUserPoolsCustomMessageResponse
+)
+
+// Define auth challenge trigger.
+type (
+	DefineAuthChallengeEvent    = events.CognitoEventUserPoolsDefineAuthChallenge
+	DefineAuthChallengeRequest  = events.CognitoEventUserPoolsDefineAuthChallengeRequest
+	DefineAuthChallengeResponse = events.CognitoEventUserPoolsDefineAuthChallengeResponse
+)
+
+// Create auth challenge trigger.
+type (
+	CreateAuthChallengeEvent    = events.CognitoEventUserPoolsCreateAuthChallenge
+	CreateAuthChallengeRequest  = events.CognitoEventUserPoolsCreateAuthChallengeRequest
+	CreateAuthChallengeResponse = events.CognitoEventUserPoolsCreateAuthChallengeResponse
+)
+
+// Verify auth challenge trigger.
+type (
+	VerifyAuthChallengeEvent    = events.CognitoEventUserPoolsVerifyAuthChallenge
+	VerifyAuthChallengeRequest  = events.CognitoEventUserPoolsVerifyAuthChallengeRequest
+	VerifyAuthChallengeResponse = events.CognitoEventUserPoolsVerifyAuthChallengeResponse
+)
+
+// User migration trigger.
+type (
+	UserMigrationEvent    = events.CognitoEventUserPoolsMigrateUser
+	UserMigrationRequest  = events.CognitoEventUserPoolsMigrateUserRequest
+	UserMigrationResponse = events.CognitoEventUserPoolsMigrateUserResponse
+)
+
+// ChallengeResult is a challenge presented to the user during a custom authentication flow, along with its result.
+type ChallengeResult = events.CognitoEventUserPoolsChallengeResult
+
+// Trigger sources sent on the "triggerSource" field of the events.
+const (
+	TriggerPreSignUpSignUp                       = "PreSignUp_SignUp"
+	TriggerPreSignUpAdminCreateUser              = "PreSignUp_AdminCreateUser"
+	TriggerPreSignUpExternalProvider             = "PreSignUp_ExternalProvider"
+	TriggerPostConfirmationConfirmSignUp         = "PostConfirmation_ConfirmSignUp"
+	TriggerPostConfirmationConfirmForgotPassword = "PostConfirmation_ConfirmForgotPassword"
+	TriggerCustomMessageSignUp                   = "CustomMessage_SignUp"
+	TriggerCustomMessageAdminCreateUser          = "CustomMessage_AdminCreateUser"
+	TriggerCustomMessageResendCode               = "CustomMessage_ResendCode"
+	TriggerCustomMessageForgotPassword           = "CustomMessage_ForgotPassword"
+	TriggerCustomMessageUpdateUserAttribute      = "CustomMessage_UpdateUserAttribute"
+	TriggerCustomMessageVerifyUserAttribute      = "CustomMessage_VerifyUserAttribute"
+	TriggerCustomMessageAuthentication           = "CustomMessage_Authentication"
+	TriggerTokenGenerationHostedAuth             = "TokenGeneration_HostedAuth"
+	TriggerTokenGenerationAuthentication         = "TokenGeneration_Authentication"
+	TriggerTokenGenerationNewPasswordChallenge   = "TokenGeneration_NewPasswordChallenge"
+	TriggerTokenGenerationAuthenticateDevice     = "TokenGeneration_AuthenticateDevice"
+	TriggerTokenGenerationRefreshTokens          = "TokenGeneration_RefreshTokens"
+	TriggerUserMigrationAuthentication           = "UserMigration_Authentication"
+	TriggerUserMigrationForgotPassword           = "UserMigration_ForgotPassword"
+)
+
+// Challenge names used by the custom authentication flow triggers.
+const (
+	ChallengeCustom           = "CUSTOM_CHALLENGE"
+	ChallengePasswordVerifier = "PASSWORD_VERIFIER"
+	ChallengeSRPA             = "SRP_A"
+)
+
+// Values accepted by the user migration response.
+const (
+	FinalUserStatusConfirmed     = "CONFIRMED"
+	FinalUserStatusResetRequired = "RESET_REQUIRED"
+	MessageActionSuppress        = "SUPPRESS"
+	DeliveryMediumEmail          = "EMAIL"
+	DeliveryMediumSMS            = "SMS"
+)
@@ -0,0 +1,76 @@
+package destinations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Drafteame/engine"
+)
+
+// ErrDecodingEnvelope is returned when the payloads of the envelope do not match the expected types.
+var ErrDecodingEnvelope = errors.New("destinations: decoding envelope failed")
+
+// InvocationFunc processes the outcome of an asynchronous invocation.
+type InvocationFunc[Req, Res any] func(context.Context, Invocation[Req, Res]) error
+
+// NewHandler creates a handler for functions configured as the Lambda destination of another function.
+func NewHandler[Req, Res any](fn InvocationFunc[Req, Res]) engine.Handler[Event, struct{}] {
+	return func(ctx context.Context, evt Event) (struct{}, error) {
+		inv, err := Decode[Req, Res](evt)
+		if err != nil {
+			return struct{}{}, err
+		}
+
+		return struct{}{}, fn(ctx, inv)
+	}
+}
+
+// Unmarshal decodes an envelope delivered through an SQS, SNS or EventBridge destination.
+func Unmarshal[Req, Res any](data []byte) (Invocation[Req, Res], error) {
+	var evt Event
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return Invocation[Req, Res]{}, errors.Join(err, ErrDecodingEnvelope)
+	}
+
+	return Decode[Req, Res](evt)
+}
+
+// Decode decodes the payloads of the envelope. The response payload is decoded into "Res" when the invocation
+// succeeded, and into an InvocationError when the function failed.
+func Decode[Req, Res any](evt Event) (Invocation[Req, Res], error) {
+	inv := Invocation[Req, Res]{Event: evt}
+
+	if err := decodeRaw(evt.RequestPayload, &inv.Request); err != nil {
+		return inv, err
+	}
+
+	if evt.ResponseContext.FunctionError != "" {
+		inv.Err = &InvocationError{}
+
+		if err := decodeRaw(evt.ResponsePayload, inv.Err); err != nil {
+			return inv, err
+		}
+
+		return inv, nil
+	}
+
+	if err := decodeRaw(evt.ResponsePayload, &inv.Response); err != nil {
+		return inv, err
+	}
+
+	return inv, nil
+}
+
+func decodeRaw(raw json.RawMessage, v any) error {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errors.Join(err, ErrDecodingEnvelope)
+	}
+
+	return nil
+}
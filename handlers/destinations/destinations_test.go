@@ -0,0 +1,85 @@
+package destinations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type orderRequest struct {
+	OrderID string `json:"orderId"`
+}
+
+type orderResponse struct {
+	Status string `json:"status"`
+}
+
+const failureEnvelope = `{
+	"version": "1.0",
+	"timestamp": "2024-01-01T10:00:00.000Z",
+	"requestContext": {
+		"requestId": "req-1",
+		"functionArn": "arn:aws:lambda:us-east-1:123456789012:function:orders:$LATEST",
+		"condition": "RetriesExhausted",
+		"approximateInvokeCount": 3
+	},
+	"requestPayload": {"orderId": "42"},
+	"responseContext": {"statusCode": 200, "executedVersion": "$LATEST", "functionError": "Unhandled"},
+	"responsePayload": {"errorMessage": "payment declined", "errorType": "errorString"}
+}`
+
+const successEnvelope = `{
+	"version": "1.0",
+	"timestamp": "2024-01-01T10:00:00.000Z",
+	"requestContext": {"requestId": "req-2", "condition": "Success", "approximateInvokeCount": 1},
+	"requestPayload": {"orderId": "43"},
+	"responseContext": {"statusCode": 200, "executedVersion": "$LATEST"},
+	"responsePayload": {"status": "paid"}
+}`
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should decode failed invocation", func(t *testing.T) {
+		var got Invocation[orderRequest, orderResponse]
+
+		handler := NewHandler(func(_ context.Context, inv Invocation[orderRequest, orderResponse]) error {
+			got = inv
+			return nil
+		})
+
+		var evt Event
+		require.NoError(t, json.Unmarshal([]byte(failureEnvelope), &evt))
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.False(t, got.Succeeded())
+		assert.Equal(t, "42", got.Request.OrderID)
+		assert.Equal(t, 3, got.Event.RequestContext.ApproximateInvokeCount)
+		require.NotNil(t, got.Err)
+		assert.Equal(t, "payment declined", got.Err.ErrorMessage)
+		assert.Equal(t, orderResponse{}, got.Response)
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("should decode successful invocation", func(t *testing.T) {
+		inv, err := Unmarshal[orderRequest, orderResponse]([]byte(successEnvelope))
+
+		require.NoError(t, err)
+		assert.True(t, inv.Succeeded())
+		assert.Nil(t, inv.Err)
+		assert.Equal(t, "43", inv.Request.OrderID)
+		assert.Equal(t, "paid", inv.Response.Status)
+	})
+
+	t.Run("should fail on mismatched payload", func(t *testing.T) {
+		_, err := Unmarshal[[]string, orderResponse]([]byte(successEnvelope))
+
+		assert.ErrorIs(t, err, ErrDecodingEnvelope)
+	})
+}
@@ -0,0 +1,67 @@
+package destinations
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Invocation conditions sent on the "condition" field of the request context.
+const (
+	ConditionSuccess          = "Success"
+	ConditionRetriesExhausted = "RetriesExhausted"
+	ConditionEventAgeExceeded = "EventAgeExceeded"
+)
+
+// Event is the envelope sent to the on-success and on-failure destinations of an asynchronous invocation. Lambda
+// destinations receive it as the event, while SQS, SNS and EventBridge destinations carry it in the message body.
+type Event struct {
+	Version         string          `json:"version"`
+	Timestamp       time.Time       `json:"timestamp"`
+	RequestContext  RequestContext  `json:"requestContext"`
+	RequestPayload  json.RawMessage `json:"requestPayload"`
+	ResponseContext ResponseContext `json:"responseContext"`
+	ResponsePayload json.RawMessage `json:"responsePayload"`
+}
+
+// RequestContext describes the original invocation.
+type RequestContext struct {
+	RequestID              string `json:"requestId"`
+	FunctionARN            string `json:"functionArn"`
+	Condition              string `json:"condition"`
+	ApproximateInvokeCount int    `json:"approximateInvokeCount"`
+}
+
+// ResponseContext describes the result of the original invocation.
+type ResponseContext struct {
+	StatusCode      int    `json:"statusCode"`
+	ExecutedVersion string `json:"executedVersion"`
+	FunctionError   string `json:"functionError,omitempty"`
+}
+
+// InvocationError is the error returned by the original invocation.
+type InvocationError struct {
+	ErrorMessage string   `json:"errorMessage"`
+	ErrorType    string   `json:"errorType"`
+	StackTrace   []string `json:"stackTrace,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *InvocationError) Error() string {
+	return e.ErrorType + ": " + e.ErrorMessage
+}
+
+// Invocation is the destination envelope with the original payload decoded into "Req" and, on success, the original
+// response decoded into "Res".
+type Invocation[Req, Res any] struct {
+	Event    Event
+	Request  Req
+	Response Res
+
+	// Err is the error of the original invocation, nil when it succeeded or never ran.
+	Err *InvocationError
+}
+
+// Succeeded reports whether the original invocation succeeded.
+func (i Invocation[Req, Res]) Succeeded() bool {
+	return i.Event.RequestContext.Condition == ConditionSuccess
+}
@@ -0,0 +1,111 @@
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Drafteame/engine"
+)
+
+// MaxResponseSize is the maximum size of a synchronous Lambda response payload.
+const MaxResponseSize = 6 * 1024 * 1024
+
+// responseEnvelopeSize is the size of the {"records":[]} wrapper around the response records.
+const responseEnvelopeSize = len(`{"records":[]}`)
+
+// TransformFunc transforms a single record. Returning an error is the same as returning ProcessingFailed.
+type TransformFunc func(context.Context, Record) (Result, error)
+
+// Config is the configuration for the Firehose transformation handler.
+type Config struct {
+	// MaxResponseSize is the size limit of the encoded response. Records that would make the response exceed it
+	// are reported as ProcessingFailed, so Firehose sends them to the error output instead of failing the batch.
+	MaxResponseSize int
+}
+
+// DefaultConfig returns the default configuration for the Firehose transformation handler.
+func DefaultConfig() Config {
+	return Config{MaxResponseSize: MaxResponseSize}
+}
+
+// NewHandler creates a Firehose transformation handler with the default configuration.
+func NewHandler(fn TransformFunc) engine.Handler[Event, Response] {
+	return NewHandlerWithConfig(fn, DefaultConfig())
+}
+
+// NewHandlerWithConfig creates a Firehose transformation handler that calls fn for every record and builds the
+// response with one record per input record, in the same order and with the same record id.
+func NewHandlerWithConfig(fn TransformFunc, config Config) engine.Handler[Event, Response] {
+	if config.MaxResponseSize <= 0 {
+		config.MaxResponseSize = MaxResponseSize
+	}
+
+	return func(ctx context.Context, evt Event) (Response, error) {
+		res := Response{Records: make([]ResponseRecord, len(evt.Records))}
+		extra := make([]int, len(evt.Records))
+
+		// every record takes at least the size of its failed form, the rest of the budget goes to transformed data
+		size := responseEnvelopeSize + max(len(evt.Records)-1, 0)
+
+		for i, rec := range evt.Records {
+			out := transform(ctx, fn, rec)
+
+			n, err := encodedSize(out)
+			if err != nil {
+				return Response{}, err
+			}
+
+			base, err := encodedSize(failed(rec.RecordID))
+			if err != nil {
+				return Response{}, err
+			}
+
+			res.Records[i] = out
+			extra[i] = n - base
+			size += base
+		}
+
+		for i, rec := range evt.Records {
+			if size+extra[i] > config.MaxResponseSize {
+				res.Records[i] = failed(rec.RecordID)
+				continue
+			}
+
+			size += extra[i]
+		}
+
+		return res, nil
+	}
+}
+
+func transform(ctx context.Context, fn TransformFunc, rec Record) ResponseRecord {
+	result, err := fn(ctx, rec)
+	if err != nil {
+		return failed(rec.RecordID)
+	}
+
+	out := ResponseRecord{RecordID: rec.RecordID, Result: result.Status}
+
+	switch result.Status {
+	case StatusOk:
+		out.Data = result.Data
+	case StatusDropped:
+	default:
+		return failed(rec.RecordID)
+	}
+
+	if len(result.PartitionKeys) > 0 {
+		out.Metadata.PartitionKeys = result.PartitionKeys
+	}
+
+	return out
+}
+
+func failed(recordID string) ResponseRecord {
+	return ResponseRecord{RecordID: recordID, Result: StatusProcessingFailed}
+}
+
+func encodedSize(rec ResponseRecord) (int, error) {
+	b, err := json.Marshal(rec)
+	return len(b), err
+}
@@ -0,0 +1,73 @@
+package firehose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should build response records in order", func(t *testing.T) {
+		handler := NewHandler(func(_ context.Context, rec Record) (Result, error) {
+			switch string(rec.Data) {
+			case "drop":
+				return Dropped(), nil
+			case "fail":
+				return Result{}, errors.New("boom")
+			default:
+				return Ok(bytes.ToUpper(rec.Data)).WithPartitionKeys(map[string]string{"tenant": "acme"}), nil
+			}
+		})
+
+		evt := Event{Records: []Record{
+			{RecordID: "1", Data: []byte("hello")},
+			{RecordID: "2", Data: []byte("drop")},
+			{RecordID: "3", Data: []byte("fail")},
+		}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+		require.NoError(t, err)
+
+		b, err := json.Marshal(res)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"records":[
+			{"recordId":"1","result":"Ok","data":"SEVMTE8=","metadata":{"partitionKeys":{"tenant":"acme"}}},
+			{"recordId":"2","result":"Dropped","data":null,"metadata":{"partitionKeys":null}},
+			{"recordId":"3","result":"ProcessingFailed","data":null,"metadata":{"partitionKeys":null}}
+		]}`, string(b))
+	})
+
+	t.Run("should fail records overflowing the response size", func(t *testing.T) {
+		handler := NewHandlerWithConfig(func(_ context.Context, rec Record) (Result, error) {
+			return Ok(rec.Data), nil
+		}, Config{MaxResponseSize: 450})
+
+		evt := Event{Records: []Record{
+			{RecordID: "1", Data: bytes.Repeat([]byte("a"), 100)},
+			{RecordID: "2", Data: bytes.Repeat([]byte("b"), 100)},
+			{RecordID: "3", Data: []byte("c")},
+		}}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+		require.NoError(t, err)
+
+		require.Len(t, res.Records, 3)
+		assert.Equal(t, StatusOk, res.Records[0].Result)
+		assert.Equal(t, StatusProcessingFailed, res.Records[1].Result)
+		assert.Nil(t, res.Records[1].Data)
+		assert.Equal(t, "2", res.Records[1].RecordID)
+		assert.Equal(t, StatusOk, res.Records[2].Result)
+
+		b, err := json.Marshal(res)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(b), 450)
+	})
+}
@@ -0,0 +1,52 @@
+package firehose
+
+import "github.com/aws/aws-lambda-go/events"
+
+// Event is the batch of records sent by Kinesis Data Firehose to a data transformation Lambda.
+type Event = events.KinesisFirehoseEvent
+
+// Record is a single record of the batch, with its data already base64 decoded.
+type Record = events.KinesisFirehoseEventRecord
+
+// Response is the transformation response expected by Firehose.
+type Response = events.KinesisFirehoseResponse
+
+// ResponseRecord is the transformation result of a single record.
+type ResponseRecord = events.KinesisFirehoseResponseRecord
+
+// Transformation statuses.
+const (
+	StatusOk               = events.KinesisFirehoseTransformedStateOk
+	StatusDropped          = events.KinesisFirehoseTransformedStateDropped
+	StatusProcessingFailed = events.KinesisFirehoseTransformedStateProcessingFailed
+)
+
+// Result is the outcome of the transformation of a single record.
+type Result struct {
+	Status string
+	Data   []byte
+
+	// PartitionKeys are the dynamic partitioning keys of the record.
+	PartitionKeys map[string]string
+}
+
+// Ok returns a successful result with the transformed data.
+func Ok(data []byte) Result {
+	return Result{Status: StatusOk, Data: data}
+}
+
+// Dropped returns a result that drops the record from the delivery stream.
+func Dropped() Result {
+	return Result{Status: StatusDropped}
+}
+
+// ProcessingFailed returns a result that sends the original record to the error output of the delivery stream.
+func ProcessingFailed() Result {
+	return Result{Status: StatusProcessingFailed}
+}
+
+// WithPartitionKeys returns a copy of the result with the given dynamic partitioning keys.
+func (r Result) WithPartitionKeys(keys map[string]string) Result {
+	r.PartitionKeys = keys
+	return r
+}
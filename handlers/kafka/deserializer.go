@@ -0,0 +1,198 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Deserializer decodes the key or value of a record.
+type Deserializer[T any] interface {
+	Deserialize(ctx context.Context, topic string, data []byte) (T, error)
+}
+
+// DeserializerFunc adapts a function to the Deserializer interface.
+type DeserializerFunc[T any] func(ctx context.Context, topic string, data []byte) (T, error)
+
+// Deserialize implements Deserializer.
+func (f DeserializerFunc[T]) Deserialize(ctx context.Context, topic string, data []byte) (T, error) {
+	return f(ctx, topic, data)
+}
+
+// Raw returns a Deserializer that keeps the data as is.
+func Raw() Deserializer[[]byte] {
+	return DeserializerFunc[[]byte](func(_ context.Context, _ string, data []byte) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// String returns a Deserializer that converts the data to a string.
+func String() Deserializer[string] {
+	return DeserializerFunc[string](func(_ context.Context, _ string, data []byte) (string, error) {
+		return string(data), nil
+	})
+}
+
+// JSON returns a Deserializer that decodes JSON data into "T". Empty data decodes to the zero value.
+func JSON[T any]() Deserializer[T] {
+	return DeserializerFunc[T](func(_ context.Context, _ string, data []byte) (T, error) {
+		var v T
+
+		if len(data) == 0 {
+			return v, nil
+		}
+
+		err := json.Unmarshal(data, &v)
+
+		return v, err
+	})
+}
+
+// Schema is a schema stored in a schema registry.
+type Schema struct {
+	ID         int
+	Type       string
+	Definition string
+}
+
+// SchemaRegistry looks up schemas by id, usually backed by a Confluent or Glue compatible schema registry client.
+type SchemaRegistry interface {
+	SchemaByID(ctx context.Context, id int) (Schema, error)
+}
+
+// SchemaRegistryFunc adapts a function to the SchemaRegistry interface.
+type SchemaRegistryFunc func(ctx context.Context, id int) (Schema, error)
+
+// SchemaByID implements SchemaRegistry.
+func (f SchemaRegistryFunc) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	return f(ctx, id)
+}
+
+type cachedRegistry struct {
+	registry SchemaRegistry
+	mu       sync.RWMutex
+	schemas  map[int]Schema
+}
+
+// CachedRegistry keeps the schemas returned by registry for the lifetime of the execution environment. Schemas are
+// immutable once registered, so they never need to be refreshed.
+func CachedRegistry(registry SchemaRegistry) SchemaRegistry {
+	return &cachedRegistry{registry: registry, schemas: make(map[int]Schema)}
+}
+
+func (c *cachedRegistry) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	c.mu.RLock()
+	s, ok := c.schemas[id]
+	c.mu.RUnlock()
+
+	if ok {
+		return s, nil
+	}
+
+	s, err := c.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return Schema{}, errors.Join(err, ErrSchemaNotFound)
+	}
+
+	c.mu.Lock()
+	c.schemas[id] = s
+	c.mu.Unlock()
+
+	return s, nil
+}
+
+// AvroUnmarshalFunc decodes Avro binary data written with the given schema into v.
+type AvroUnmarshalFunc[T any] func(schema Schema, data []byte, v *T) error
+
+// Avro returns a Deserializer for values serialized in the schema registry wire format: a zero magic byte and a
+// big endian schema id followed by the Avro payload. Decoding is delegated to unmarshal, so any Avro library can be
+// used.
+func Avro[T any](registry SchemaRegistry, unmarshal AvroUnmarshalFunc[T]) Deserializer[T] {
+	return DeserializerFunc[T](func(ctx context.Context, _ string, data []byte) (T, error) {
+		var v T
+
+		id, payload, err := splitWireFormat(data)
+		if err != nil {
+			return v, err
+		}
+
+		schema, err := registry.SchemaByID(ctx, id)
+		if err != nil {
+			return v, err
+		}
+
+		err = unmarshal(schema, payload, &v)
+
+		return v, err
+	})
+}
+
+// ProtobufUnmarshalFunc decodes Protobuf data into v. The message indexes locate the message type inside the schema.
+type ProtobufUnmarshalFunc[T any] func(schema Schema, indexes []int, data []byte, v *T) error
+
+// Protobuf returns a Deserializer for values serialized in the schema registry wire format: a zero magic byte, a
+// big endian schema id and the varint encoded message indexes followed by the Protobuf payload. Decoding is
+// delegated to unmarshal, so generated message types can be used directly.
+func Protobuf[T any](registry SchemaRegistry, unmarshal ProtobufUnmarshalFunc[T]) Deserializer[T] {
+	return DeserializerFunc[T](func(ctx context.Context, _ string, data []byte) (T, error) {
+		var v T
+
+		id, payload, err := splitWireFormat(data)
+		if err != nil {
+			return v, err
+		}
+
+		indexes, payload, err := splitMessageIndexes(payload)
+		if err != nil {
+			return v, err
+		}
+
+		schema, err := registry.SchemaByID(ctx, id)
+		if err != nil {
+			return v, err
+		}
+
+		err = unmarshal(schema, indexes, payload, &v)
+
+		return v, err
+	})
+}
+
+func splitWireFormat(data []byte) (int, []byte, error) {
+	if len(data) < 5 || data[0] != 0 {
+		return 0, nil, ErrInvalidWireFormat
+	}
+
+	return int(binary.BigEndian.Uint32(data[1:5])), data[5:], nil
+}
+
+// splitMessageIndexes reads the zigzag varint encoded message indexes. A single zero stands for the first message
+// of the schema.
+func splitMessageIndexes(data []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 || count > int64(len(data)) {
+		return nil, nil, ErrInvalidWireFormat
+	}
+
+	data = data[n:]
+
+	if count == 0 {
+		return []int{0}, data, nil
+	}
+
+	indexes := make([]int, count)
+
+	for i := range indexes {
+		idx, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, nil, ErrInvalidWireFormat
+		}
+
+		indexes[i] = int(idx)
+		data = data[n:]
+	}
+
+	return indexes, data, nil
+}
@@ -0,0 +1,10 @@
+package kafka
+
+import "errors"
+
+var (
+	ErrDecodingRecord      = errors.New("kafka: decoding record failed")
+	ErrInvalidWireFormat   = errors.New("kafka: invalid schema registry wire format")
+	ErrSchemaNotFound      = errors.New("kafka: schema not found")
+	ErrMissingDeserializer = errors.New("kafka: missing key or value deserializer")
+)
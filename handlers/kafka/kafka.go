@@ -0,0 +1,190 @@
+package kafka
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/Drafteame/engine"
+)
+
+// RecordFunc processes a single decoded record.
+type RecordFunc[K, V any] func(context.Context, Record[K, V]) error
+
+// Config is the configuration for the Kafka handler.
+type Config[K, V any] struct {
+	// Key decodes record keys. It is required.
+	Key Deserializer[K]
+
+	// Value decodes record values. It is required.
+	Value Deserializer[V]
+
+	// Concurrency is how many topic-partitions are processed at the same time. Records of the same partition are
+	// always processed in order. Defaults to 1.
+	Concurrency int
+}
+
+// NewHandler creates a Kafka handler that calls fn for every record of the batch, in offset order inside each
+// topic-partition. Processing of a partition stops at its first failing record and the handler returns the joined
+// errors of every partition, so the event source mapping retries the batch. Without a Key or Value deserializer the
+// handler returns ErrMissingDeserializer.
+func NewHandler[K, V any](fn RecordFunc[K, V], config Config[K, V]) engine.Handler[Event, struct{}] {
+	if config.Key == nil || config.Value == nil {
+		return func(context.Context, Event) (struct{}, error) {
+			return struct{}{}, ErrMissingDeserializer
+		}
+	}
+
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+
+	return func(ctx context.Context, evt Event) (struct{}, error) {
+		partitions, err := Partitions(evt)
+		if err != nil {
+			return struct{}{}, err
+		}
+
+		errs := make([]error, len(partitions))
+		sem := make(chan struct{}, config.Concurrency)
+
+		var wg sync.WaitGroup
+
+		for i, records := range partitions {
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				errs[i] = processPartition(ctx, fn, config, records)
+			}()
+		}
+
+		wg.Wait()
+
+		return struct{}{}, errors.Join(errs...)
+	}
+}
+
+// Records flattens and decodes the records of every topic-partition of the event, ordered by topic, partition and
+// offset.
+func Records(evt Event) ([]RawRecord, error) {
+	partitions, err := Partitions(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(partitions...), nil
+}
+
+// Partitions decodes the records of the event grouped by topic-partition, ordered by topic, partition and offset.
+func Partitions(evt Event) ([][]RawRecord, error) {
+	keys := make([]string, 0, len(evt.Records))
+	for k := range evt.Records {
+		keys = append(keys, k)
+	}
+
+	partitions := make([][]RawRecord, 0, len(keys))
+
+	for _, k := range keys {
+		records := make([]RawRecord, 0, len(evt.Records[k]))
+
+		for _, r := range evt.Records[k] {
+			raw, err := decodeRecord(r)
+			if err != nil {
+				return nil, err
+			}
+
+			records = append(records, raw)
+		}
+
+		slices.SortStableFunc(records, func(a, b RawRecord) int {
+			return cmp.Compare(a.Offset, b.Offset)
+		})
+
+		if len(records) > 0 {
+			partitions = append(partitions, records)
+		}
+	}
+
+	slices.SortFunc(partitions, func(a, b []RawRecord) int {
+		return cmp.Or(cmp.Compare(a[0].Topic, b[0].Topic), cmp.Compare(a[0].Partition, b[0].Partition))
+	})
+
+	return partitions, nil
+}
+
+func processPartition[K, V any](ctx context.Context, fn RecordFunc[K, V], config Config[K, V], records []RawRecord) error {
+	for _, raw := range records {
+		rec, err := decode(ctx, config, raw)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(ctx, rec); err != nil {
+			return fmt.Errorf("kafka: record %s-%d@%d: %w", raw.Topic, raw.Partition, raw.Offset, err)
+		}
+	}
+
+	return nil
+}
+
+func decode[K, V any](ctx context.Context, config Config[K, V], raw RawRecord) (Record[K, V], error) {
+	rec := Record[K, V]{RawRecord: raw}
+
+	key, err := config.Key.Deserialize(ctx, raw.Topic, raw.Key)
+	if err != nil {
+		return rec, errors.Join(err, ErrDecodingRecord)
+	}
+
+	value, err := config.Value.Deserialize(ctx, raw.Topic, raw.Value)
+	if err != nil {
+		return rec, errors.Join(err, ErrDecodingRecord)
+	}
+
+	rec.Key = key
+	rec.Value = value
+
+	return rec, nil
+}
+
+func decodeRecord(r events.KafkaRecord) (RawRecord, error) {
+	key, err := base64.StdEncoding.DecodeString(r.Key)
+	if err != nil {
+		return RawRecord{}, errors.Join(err, ErrDecodingRecord)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(r.Value)
+	if err != nil {
+		return RawRecord{}, errors.Join(err, ErrDecodingRecord)
+	}
+
+	headers := make(map[string]string)
+
+	for _, h := range r.Headers {
+		for k, v := range h {
+			headers[k] = string(v)
+		}
+	}
+
+	return RawRecord{
+		Topic:         r.Topic,
+		Partition:     r.Partition,
+		Offset:        r.Offset,
+		Timestamp:     r.Timestamp.Time,
+		TimestampType: r.TimestampType,
+		Key:           key,
+		Value:         value,
+		Headers:       headers,
+	}, nil
+}
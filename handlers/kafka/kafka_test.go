@@ -0,0 +1,158 @@
+package kafka
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type order struct {
+	ID string `json:"id"`
+}
+
+func record(topic string, partition, offset int64, key, value string) events.KafkaRecord {
+	return events.KafkaRecord{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		Key:       base64.StdEncoding.EncodeToString([]byte(key)),
+		Value:     base64.StdEncoding.EncodeToString([]byte(value)),
+		Headers:   []map[string]events.JSONNumberBytes{{"source": []byte("checkout")}},
+	}
+}
+
+func testEvent() Event {
+	return Event{
+		Records: map[string][]events.KafkaRecord{
+			"orders-1": {record("orders", 1, 7, "k3", `{"id":"3"}`)},
+			"orders-0": {
+				record("orders", 0, 11, "k2", `{"id":"2"}`),
+				record("orders", 0, 10, "k1", `{"id":"1"}`),
+			},
+		},
+	}
+}
+
+func TestRecords(t *testing.T) {
+	t.Run("should flatten and decode records in order", func(t *testing.T) {
+		records, err := Records(testEvent())
+
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []byte("k1"), records[0].Key)
+		assert.Equal(t, []byte(`{"id":"2"}`), records[1].Value)
+		assert.Equal(t, int64(1), records[2].Partition)
+		assert.Equal(t, map[string]string{"source": "checkout"}, records[0].Headers)
+	})
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should process decoded records in partition order", func(t *testing.T) {
+		var (
+			mu  sync.Mutex
+			ids []string
+		)
+
+		handler := NewHandler(func(_ context.Context, rec Record[string, order]) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			ids = append(ids, rec.Key+":"+rec.Value.ID)
+
+			return nil
+		}, Config[string, order]{Key: String(), Value: JSON[order]()})
+
+		_, err := testengine.New(context.Background(), testEvent(), handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k1:1", "k2:2", "k3:3"}, ids)
+	})
+
+	t.Run("should stop a partition at its first failure", func(t *testing.T) {
+		var (
+			mu   sync.Mutex
+			seen []int64
+		)
+
+		handler := NewHandler(func(_ context.Context, rec Record[string, order]) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			seen = append(seen, rec.Offset)
+
+			if rec.Offset == 10 {
+				return errors.New("boom")
+			}
+
+			return nil
+		}, Config[string, order]{Key: String(), Value: JSON[order](), Concurrency: 2})
+
+		_, err := testengine.New(context.Background(), testEvent(), handler).Run()
+
+		assert.EqualError(t, err, "kafka: record orders-0@10: boom")
+		assert.ElementsMatch(t, []int64{10, 7}, seen)
+	})
+
+	t.Run("should fail without deserializers", func(t *testing.T) {
+		handler := NewHandler(func(context.Context, Record[string, order]) error {
+			return nil
+		}, Config[string, order]{Key: String()})
+
+		_, err := testengine.New(context.Background(), testEvent(), handler).Run()
+
+		assert.ErrorIs(t, err, ErrMissingDeserializer)
+	})
+}
+
+func TestProtobuf(t *testing.T) {
+	t.Run("should split wire format and look up schema once", func(t *testing.T) {
+		lookups := 0
+		registry := CachedRegistry(SchemaRegistryFunc(func(_ context.Context, id int) (Schema, error) {
+			lookups++
+			return Schema{ID: id, Type: "PROTOBUF"}, nil
+		}))
+
+		des := Protobuf(registry, func(schema Schema, indexes []int, data []byte, v *string) error {
+			assert.Equal(t, 42, schema.ID)
+			assert.Equal(t, []int{1, 0}, indexes)
+
+			*v = string(data)
+
+			return nil
+		})
+
+		data := []byte{0, 0, 0, 0, 42}
+		data = binary.AppendVarint(data, 2)
+		data = binary.AppendVarint(data, 1)
+		data = binary.AppendVarint(data, 0)
+		data = append(data, "payload"...)
+
+		for range 2 {
+			v, err := des.Deserialize(context.Background(), "orders", data)
+
+			require.NoError(t, err)
+			assert.Equal(t, "payload", v)
+		}
+
+		assert.Equal(t, 1, lookups)
+	})
+
+	t.Run("should reject data without magic byte", func(t *testing.T) {
+		des := Avro(SchemaRegistryFunc(func(context.Context, int) (Schema, error) {
+			return Schema{}, nil
+		}), func(Schema, []byte, *string) error { return nil })
+
+		_, err := des.Deserialize(context.Background(), "orders", []byte(`{"id":"1"}`))
+
+		assert.ErrorIs(t, err, ErrInvalidWireFormat)
+	})
+}
@@ -0,0 +1,29 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Event is the batch of records sent by an MSK or self-managed Kafka event source mapping.
+type Event = events.KafkaEvent
+
+// RawRecord is a Kafka record with its key and value base64 decoded.
+type RawRecord struct {
+	Topic         string
+	Partition     int64
+	Offset        int64
+	Timestamp     time.Time
+	TimestampType string
+	Key           []byte
+	Value         []byte
+	Headers       map[string]string
+}
+
+// Record is a Kafka record with its key decoded into "K" and its value decoded into "V".
+type Record[K, V any] struct {
+	RawRecord
+	Key   K
+	Value V
+}
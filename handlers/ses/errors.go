@@ -0,0 +1,8 @@
+package ses
+
+import "errors"
+
+var (
+	ErrFetchingMessage = errors.New("ses: fetching message failed")
+	ErrParsingMessage  = errors.New("ses: parsing message failed")
+)
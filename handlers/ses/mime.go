@@ -0,0 +1,105 @@
+package ses
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+var wordDecoder = new(mime.WordDecoder)
+
+// ParseMessage parses a raw MIME email, collecting its first text and HTML bodies and its attachments. Nested
+// multipart bodies are walked depth first. Bodies keep their original charset.
+func ParseMessage(r io.Reader) (*Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, errors.Join(err, ErrParsingMessage)
+	}
+
+	out := &Message{Header: msg.Header}
+
+	if err := parsePart(out, textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, errors.Join(err, ErrParsingMessage)
+	}
+
+	return out, nil
+}
+
+func parsePart(out *Message, header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+
+		for {
+			part, err := mr.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := parsePart(out, part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+
+	filename := dispParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+
+	if decoded, err := wordDecoder.DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+
+	isAttachment := disposition == "attachment" || filename != "" ||
+		(mediaType != "text/plain" && mediaType != "text/html")
+
+	switch {
+	case !isAttachment && mediaType == "text/plain" && out.Text == "":
+		out.Text = string(data)
+	case !isAttachment && mediaType == "text/html" && out.HTML == "":
+		out.HTML = string(data)
+	case isAttachment:
+		out.Attachments = append(out.Attachments, Attachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			ContentID:   strings.Trim(header.Get("Content-Id"), "<>"),
+			Inline:      disposition == "inline",
+			Data:        data,
+		})
+	}
+
+	return nil
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
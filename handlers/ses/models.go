@@ -0,0 +1,90 @@
+package ses
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Event is the receipt event sent by an SES receipt rule Lambda action.
+type Event = events.SimpleEmailEvent
+
+// Response tells SES how to continue with the rule set on synchronous invocations.
+type Response = events.SimpleEmailDisposition
+
+// Disposition is the rule set disposition returned on synchronous invocations.
+type Disposition = events.SimpleEmailDispositionValue
+
+// Dispositions accepted by SES.
+const (
+	Continue    = events.SimpleEmailContinue
+	StopRule    = events.SimpleEmailStopRule
+	StopRuleSet = events.SimpleEmailStopRuleSet
+)
+
+// Verdict statuses reported by SES.
+const (
+	VerdictPass           = "PASS"
+	VerdictFail           = "FAIL"
+	VerdictGray           = "GRAY"
+	VerdictProcessingFail = "PROCESSING_FAILED"
+	VerdictDisabled       = "DISABLED"
+)
+
+// Verdicts contains the results of the spam, virus and authentication checks run by SES.
+type Verdicts struct {
+	Spam        string
+	Virus       string
+	SPF         string
+	DKIM        string
+	DMARC       string
+	DMARCPolicy string
+}
+
+// Passed reports whether the spam, virus, SPF and DKIM checks passed.
+func (v Verdicts) Passed() bool {
+	return v.Spam == VerdictPass && v.Virus == VerdictPass && v.SPF == VerdictPass && v.DKIM == VerdictPass
+}
+
+// Email is a received email.
+type Email struct {
+	MessageID   string
+	Source      string
+	Destination []string
+	Recipients  []string
+	Timestamp   time.Time
+
+	From       []string
+	To         []string
+	ReturnPath string
+	Subject    string
+	Date       string
+
+	// Headers contains every header of the email, unless SES truncated them.
+	Headers          mail.Header
+	HeadersTruncated bool
+
+	Verdicts Verdicts
+	Action   events.SimpleEmailReceiptAction
+
+	// Message is the parsed content of the email. It is only set when a Fetcher is configured.
+	Message *Message
+}
+
+// Message is a parsed MIME message.
+type Message struct {
+	Header      mail.Header
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to, or embedded inline in, a message.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	ContentID   string
+	Inline      bool
+	Data        []byte
+}
@@ -0,0 +1,133 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/mail"
+	"net/textproto"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/Drafteame/engine"
+)
+
+// Fetcher retrieves the raw email stored by a previous S3 receipt rule action, usually through an S3 GetObject call.
+type Fetcher interface {
+	Fetch(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface.
+type FetcherFunc func(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+
+// Fetch implements Fetcher.
+func (f FetcherFunc) Fetch(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	return f(ctx, bucket, key)
+}
+
+// EmailFunc processes a received email and returns the disposition for the rule set. An empty disposition is the
+// same as Continue.
+type EmailFunc func(context.Context, Email) (Disposition, error)
+
+// Config is the configuration for the SES handler.
+type Config struct {
+	// Fetcher retrieves the raw email to parse it. Messages are not parsed when it is nil.
+	Fetcher Fetcher
+
+	// Bucket and KeyPrefix locate the raw email stored by the S3 action of the rule, whose object key is the prefix
+	// followed by the message id. They are not needed when the event comes from the S3 action itself.
+	Bucket    string
+	KeyPrefix string
+}
+
+// NewHandler creates an SES receipt handler. When several records are received, the most restrictive disposition
+// is returned.
+func NewHandler(fn EmailFunc, config Config) engine.Handler[Event, Response] {
+	return func(ctx context.Context, evt Event) (Response, error) {
+		res := Response{Disposition: Continue}
+
+		for _, rec := range evt.Records {
+			email := newEmail(rec.SES)
+
+			if config.Fetcher != nil {
+				msg, err := fetchMessage(ctx, config, email)
+				if err != nil {
+					return Response{}, err
+				}
+
+				email.Message = msg
+			}
+
+			disposition, err := fn(ctx, email)
+			if err != nil {
+				return Response{}, err
+			}
+
+			if rank(disposition) > rank(res.Disposition) {
+				res.Disposition = disposition
+			}
+		}
+
+		return res, nil
+	}
+}
+
+func newEmail(s events.SimpleEmailService) Email {
+	headers := make(mail.Header, len(s.Mail.Headers))
+	for _, h := range s.Mail.Headers {
+		key := textproto.CanonicalMIMEHeaderKey(h.Name)
+		headers[key] = append(headers[key], h.Value)
+	}
+
+	return Email{
+		MessageID:        s.Mail.MessageID,
+		Source:           s.Mail.Source,
+		Destination:      s.Mail.Destination,
+		Recipients:       s.Receipt.Recipients,
+		Timestamp:        s.Mail.Timestamp,
+		From:             s.Mail.CommonHeaders.From,
+		To:               s.Mail.CommonHeaders.To,
+		ReturnPath:       s.Mail.CommonHeaders.ReturnPath,
+		Subject:          s.Mail.CommonHeaders.Subject,
+		Date:             s.Mail.CommonHeaders.Date,
+		Headers:          headers,
+		HeadersTruncated: s.Mail.HeadersTruncated,
+		Verdicts: Verdicts{
+			Spam:        s.Receipt.SpamVerdict.Status,
+			Virus:       s.Receipt.VirusVerdict.Status,
+			SPF:         s.Receipt.SPFVerdict.Status,
+			DKIM:        s.Receipt.DKIMVerdict.Status,
+			DMARC:       s.Receipt.DMARCVerdict.Status,
+			DMARCPolicy: s.Receipt.DMARCPolicy,
+		},
+		Action: s.Receipt.Action,
+	}
+}
+
+func fetchMessage(ctx context.Context, config Config, email Email) (*Message, error) {
+	bucket, key := config.Bucket, config.KeyPrefix+email.MessageID
+
+	if email.Action.Type == "S3" {
+		bucket, key = email.Action.BucketName, email.Action.ObjectKey
+	}
+
+	body, err := config.Fetcher.Fetch(ctx, bucket, key)
+	if err != nil {
+		return nil, errors.Join(err, ErrFetchingMessage)
+	}
+
+	defer func() { _ = body.Close() }()
+
+	return ParseMessage(body)
+}
+
+func rank(d Disposition) int {
+	switch d {
+	case StopRuleSet:
+		return 2
+	case StopRule:
+		return 1
+	default:
+		return 0
+	}
+}
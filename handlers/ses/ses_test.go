@@ -0,0 +1,145 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+const rawEmail = "From: Alice <alice@example.com>\r\n" +
+	"To: orders@example.org\r\n" +
+	"Subject: Invoice\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Total: 10 =E2=82=AC\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Total: 10</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=\"invoice.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"invoice.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0x\r\n" +
+	"LjQ=\r\n" +
+	"--outer--\r\n"
+
+func testEvent(action events.SimpleEmailReceiptAction) Event {
+	return Event{Records: []events.SimpleEmailRecord{{
+		SES: events.SimpleEmailService{
+			Mail: events.SimpleEmailMessage{
+				MessageID: "msg-1",
+				Source:    "alice@example.com",
+				Timestamp: time.Unix(1700000000, 0),
+				Headers:   []events.SimpleEmailHeader{{Name: "x-custom", Value: "1"}, {Name: "Received", Value: "a"}, {Name: "Received", Value: "b"}},
+				CommonHeaders: events.SimpleEmailCommonHeaders{
+					From:    []string{"Alice <alice@example.com>"},
+					Subject: "Invoice",
+				},
+			},
+			Receipt: events.SimpleEmailReceipt{
+				Recipients:   []string{"orders@example.org"},
+				SpamVerdict:  events.SimpleEmailVerdict{Status: VerdictPass},
+				VirusVerdict: events.SimpleEmailVerdict{Status: VerdictPass},
+				SPFVerdict:   events.SimpleEmailVerdict{Status: VerdictPass},
+				DKIMVerdict:  events.SimpleEmailVerdict{Status: VerdictFail},
+				Action:       action,
+			},
+		},
+	}}}
+}
+
+func TestParseMessage(t *testing.T) {
+	t.Run("should parse nested bodies and attachments", func(t *testing.T) {
+		msg, err := ParseMessage(strings.NewReader(rawEmail))
+
+		require.NoError(t, err)
+		assert.Equal(t, "Invoice", msg.Header.Get("Subject"))
+		assert.Equal(t, "Total: 10 €", strings.TrimSpace(msg.Text))
+		assert.Equal(t, "<p>Total: 10</p>", msg.HTML)
+		require.Len(t, msg.Attachments, 1)
+		assert.Equal(t, "invoice.pdf", msg.Attachments[0].Filename)
+		assert.Equal(t, "application/pdf", msg.Attachments[0].ContentType)
+		assert.Equal(t, []byte("%PDF-1.4"), msg.Attachments[0].Data)
+	})
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should expose headers and verdicts", func(t *testing.T) {
+		var got Email
+
+		handler := NewHandler(func(_ context.Context, email Email) (Disposition, error) {
+			got = email
+			return StopRuleSet, nil
+		}, Config{})
+
+		res, err := testengine.New(context.Background(), testEvent(events.SimpleEmailReceiptAction{Type: "Lambda"}), handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, StopRuleSet, res.Disposition)
+		assert.Equal(t, "msg-1", got.MessageID)
+		assert.Equal(t, "1", got.Headers.Get("X-Custom"))
+		assert.Equal(t, []string{"a", "b"}, got.Headers["Received"])
+		assert.Equal(t, VerdictFail, got.Verdicts.DKIM)
+		assert.False(t, got.Verdicts.Passed())
+		assert.Nil(t, got.Message)
+	})
+
+	t.Run("should fetch and parse message from s3", func(t *testing.T) {
+		var (
+			got       Email
+			bucketKey string
+		)
+
+		fetcher := FetcherFunc(func(_ context.Context, bucket, key string) (io.ReadCloser, error) {
+			bucketKey = bucket + "/" + key
+			return io.NopCloser(strings.NewReader(rawEmail)), nil
+		})
+
+		handler := NewHandler(func(_ context.Context, email Email) (Disposition, error) {
+			got = email
+			return "", nil
+		}, Config{Fetcher: fetcher, Bucket: "mail", KeyPrefix: "inbound/"})
+
+		res, err := testengine.New(context.Background(), testEvent(events.SimpleEmailReceiptAction{Type: "Lambda"}), handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, Continue, res.Disposition)
+		assert.Equal(t, "mail/inbound/msg-1", bucketKey)
+		require.NotNil(t, got.Message)
+		assert.Len(t, got.Message.Attachments, 1)
+	})
+
+	t.Run("should fail when the message cannot be fetched", func(t *testing.T) {
+		fetcher := FetcherFunc(func(context.Context, string, string) (io.ReadCloser, error) {
+			return nil, errors.New("access denied")
+		})
+
+		handler := NewHandler(func(context.Context, Email) (Disposition, error) {
+			return Continue, nil
+		}, Config{Fetcher: fetcher})
+
+		_, err := testengine.New(context.Background(), testEvent(events.SimpleEmailReceiptAction{Type: "S3", BucketName: "b", ObjectKey: "k"}), handler).Run()
+
+		assert.ErrorIs(t, err, ErrFetchingMessage)
+	})
+}
@@ -0,0 +1,53 @@
+package stepfunctions
+
+import (
+	"errors"
+)
+
+var ErrMissingTaskToken = errors.New("stepfunctions: missing task token")
+
+// Error is an error carrying the Step Functions error type used to match Retry and Catch rules.
+type Error struct {
+	Type  string
+	Cause error
+}
+
+// NewError creates an error with the given Step Functions error type and cause message.
+func NewError(errorType, cause string) *Error {
+	return &Error{Type: errorType, Cause: errors.New(cause)}
+}
+
+// WrapError assigns the given Step Functions error type to err.
+func WrapError(errorType string, err error) *Error {
+	return &Error{Type: errorType, Cause: err}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Type
+	}
+
+	return e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// ErrorType returns the Step Functions error type.
+func (e *Error) ErrorType() string {
+	return e.Type
+}
+
+// ErrorTyper is implemented by errors that know their Step Functions error type.
+type ErrorTyper interface {
+	ErrorType() string
+}
+
+// ErrorType maps errors matching Target, as reported by errors.Is, to the Step Functions error type Name.
+type ErrorType struct {
+	Name   string
+	Target error
+}
@@ -0,0 +1,75 @@
+package stepfunctions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Drafteame/engine"
+)
+
+// TaskFunc is the business logic of a Lambda task, where "I" is the state input and "O" the state output.
+type TaskFunc[I, O any] func(context.Context, I) (O, error)
+
+// Config is the configuration for the task handlers.
+type Config struct {
+	// ErrorTypes maps errors to Step Functions error types. They are checked in order, after errors implementing
+	// ErrorTyper.
+	ErrorTypes []ErrorType
+
+	// DefaultErrorType is the error type of errors that are not mapped. When empty, the Lambda runtime default is
+	// kept, which is the Go type name of the error.
+	DefaultErrorType string
+}
+
+// DefaultConfig returns the default configuration for the task handlers.
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// NewHandler creates a task handler with the default configuration.
+func NewHandler[I, O any](fn TaskFunc[I, O]) engine.Handler[I, O] {
+	return NewHandlerWithConfig(fn, DefaultConfig())
+}
+
+// NewHandlerWithConfig creates a task handler that reports errors with their mapped Step Functions error type, so
+// state machines can match them on "ErrorEquals" of Retry and Catch rules.
+func NewHandlerWithConfig[I, O any](fn TaskFunc[I, O], config Config) engine.Handler[I, O] {
+	return func(ctx context.Context, in I) (O, error) {
+		out, err := fn(ctx, in)
+		if err != nil {
+			return out, config.invokeError(err)
+		}
+
+		return out, nil
+	}
+}
+
+// ErrorTypeOf returns the Step Functions error type of err, or an empty string when it is not mapped.
+func (c Config) ErrorTypeOf(err error) string {
+	var typer ErrorTyper
+	if errors.As(err, &typer) {
+		return typer.ErrorType()
+	}
+
+	for _, et := range c.ErrorTypes {
+		if errors.Is(err, et.Target) {
+			return et.Name
+		}
+	}
+
+	return c.DefaultErrorType
+}
+
+// invokeError attaches the Step Functions error type to err, which the runtime reports as errorType verbatim.
+func (c Config) invokeError(err error) error {
+	errorType := c.ErrorTypeOf(err)
+	if errorType == "" {
+		return err
+	}
+
+	return &engine.InvokeError{
+		Type:    errorType,
+		Message: err.Error(),
+		Err:     err,
+	}
+}
@@ -0,0 +1,174 @@
+package stepfunctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Drafteame/engine"
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+var errNotFound = errors.New("order not found")
+
+type orderInput struct {
+	OrderID string `json:"orderId"`
+}
+
+type orderOutput struct {
+	Status string `json:"status"`
+}
+
+type fakeClient struct {
+	mu         sync.Mutex
+	output     string
+	errorType  string
+	cause      string
+	heartbeats int
+	completed  bool
+	late       int
+	delay      time.Duration
+}
+
+func (c *fakeClient) SendTaskSuccess(_ context.Context, _, output string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.output = output
+
+	return nil
+}
+
+func (c *fakeClient) SendTaskFailure(_ context.Context, _, errorType, cause string) error {
+	c.mu.Lock()
+	c.errorType = errorType
+	c.cause = cause
+	c.completed = true
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+
+	return nil
+}
+
+func (c *fakeClient) SendTaskHeartbeat(context.Context, string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.heartbeats++
+
+	if c.completed {
+		c.late++
+	}
+
+	return nil
+}
+
+func TestNewHandler(t *testing.T) {
+	config := Config{ErrorTypes: []ErrorType{{Name: "OrderNotFound", Target: errNotFound}}}
+
+	t.Run("should return typed output", func(t *testing.T) {
+		handler := NewHandlerWithConfig(func(_ context.Context, in orderInput) (orderOutput, error) {
+			return orderOutput{Status: "shipped " + in.OrderID}, nil
+		}, config)
+
+		out, err := testengine.New(context.Background(), orderInput{OrderID: "42"}, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, "shipped 42", out.Status)
+	})
+
+	t.Run("should map errors to error types", func(t *testing.T) {
+		handler := NewHandlerWithConfig(func(context.Context, orderInput) (orderOutput, error) {
+			return orderOutput{}, fmt.Errorf("loading order: %w", errNotFound)
+		}, config)
+
+		_, err := testengine.New(context.Background(), orderInput{}, handler).Run()
+
+		var invokeErr *engine.InvokeError
+		require.ErrorAs(t, err, &invokeErr)
+		assert.Equal(t, "OrderNotFound", invokeErr.Type)
+		assert.Equal(t, "loading order: order not found", invokeErr.Message)
+		assert.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("should prefer error typers", func(t *testing.T) {
+		handler := NewHandlerWithConfig(func(context.Context, orderInput) (orderOutput, error) {
+			return orderOutput{}, WrapError("PaymentDeclined", errNotFound)
+		}, config)
+
+		_, err := testengine.New(context.Background(), orderInput{}, handler).Run()
+
+		var invokeErr *engine.InvokeError
+		require.ErrorAs(t, err, &invokeErr)
+		assert.Equal(t, "PaymentDeclined", invokeErr.Type)
+		assert.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("should keep unmapped errors", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		handler := NewHandler(func(context.Context, orderInput) (orderOutput, error) {
+			return orderOutput{}, boom
+		})
+
+		_, err := testengine.New(context.Background(), orderInput{}, handler).Run()
+
+		assert.Equal(t, boom, err)
+	})
+}
+
+func TestNewTaskTokenHandler(t *testing.T) {
+	t.Run("should send task success", func(t *testing.T) {
+		client := &fakeClient{}
+
+		handler := NewTaskTokenHandler(client, func(_ context.Context, in orderInput) (orderOutput, error) {
+			return orderOutput{Status: "approved " + in.OrderID}, nil
+		}, TokenHandlerConfig{})
+
+		evt := TokenInput[orderInput]{TaskToken: "token", Input: orderInput{OrderID: "42"}}
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"status":"approved 42"}`, client.output)
+	})
+
+	t.Run("should send task failure with heartbeats", func(t *testing.T) {
+		client := &fakeClient{delay: 50 * time.Millisecond}
+
+		handler := NewTaskTokenHandler(client, func(context.Context, orderInput) (orderOutput, error) {
+			time.Sleep(50 * time.Millisecond)
+			return orderOutput{}, errNotFound
+		}, TokenHandlerConfig{
+			Config:            Config{ErrorTypes: []ErrorType{{Name: "OrderNotFound", Target: errNotFound}}},
+			HeartbeatInterval: 10 * time.Millisecond,
+		})
+
+		evt := TokenInput[orderInput]{TaskToken: "token"}
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, "OrderNotFound", client.errorType)
+		assert.Equal(t, "order not found", client.cause)
+		assert.Positive(t, client.heartbeats)
+		assert.Zero(t, client.late)
+	})
+
+	t.Run("should fail without task token", func(t *testing.T) {
+		handler := NewTaskTokenHandler(&fakeClient{}, func(context.Context, orderInput) (orderOutput, error) {
+			return orderOutput{}, nil
+		}, TokenHandlerConfig{})
+
+		_, err := testengine.New(context.Background(), TokenInput[orderInput]{}, handler).Run()
+
+		assert.ErrorIs(t, err, ErrMissingTaskToken)
+	})
+}
@@ -0,0 +1,146 @@
+package stepfunctions
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Drafteame/engine"
+)
+
+const (
+	maxErrorLength = 256
+	maxCauseLength = 32768
+)
+
+// Client sends task token callbacks to Step Functions. It is usually an adapter over the SendTaskSuccess,
+// SendTaskFailure and SendTaskHeartbeat operations of the AWS SDK client.
+type Client interface {
+	SendTaskSuccess(ctx context.Context, token, output string) error
+	SendTaskFailure(ctx context.Context, token, errorType, cause string) error
+	SendTaskHeartbeat(ctx context.Context, token string) error
+}
+
+// TaskToken reports the outcome of a ".waitForTaskToken" task.
+type TaskToken struct {
+	client Client
+	token  string
+	config Config
+}
+
+// NewTaskToken creates a TaskToken for the given token. The configuration is used to map failures to error types.
+func NewTaskToken(client Client, token string, config Config) *TaskToken {
+	return &TaskToken{client: client, token: token, config: config}
+}
+
+// Token returns the raw task token.
+func (t *TaskToken) Token() string {
+	return t.token
+}
+
+// Success completes the task with the JSON encoding of output.
+func (t *TaskToken) Success(ctx context.Context, output any) error {
+	b, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+
+	return t.client.SendTaskSuccess(ctx, t.token, string(b))
+}
+
+// Failure fails the task with the mapped error type of err, or "Error" when it is not mapped.
+func (t *TaskToken) Failure(ctx context.Context, err error) error {
+	errorType := t.config.ErrorTypeOf(err)
+	if errorType == "" {
+		errorType = "Error"
+	}
+
+	return t.client.SendTaskFailure(ctx, t.token, truncate(errorType, maxErrorLength), truncate(err.Error(), maxCauseLength))
+}
+
+// Heartbeat reports the task is still in progress.
+func (t *TaskToken) Heartbeat(ctx context.Context) error {
+	return t.client.SendTaskHeartbeat(ctx, t.token)
+}
+
+// KeepAlive sends a heartbeat every interval until the returned function is called or ctx is done. Heartbeat errors
+// are ignored, since the task timing out is reported by Step Functions anyway.
+func (t *TaskToken) KeepAlive(ctx context.Context, interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = t.Heartbeat(ctx)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// TokenInput is the payload of a ".waitForTaskToken" Lambda task, built in the state machine with
+// {"taskToken.$": "$$.Task.Token", "input.$": "$"}.
+type TokenInput[I any] struct {
+	TaskToken string `json:"taskToken"`
+	Input     I      `json:"input"`
+}
+
+// TokenHandlerConfig is the configuration for the task token handler.
+type TokenHandlerConfig struct {
+	Config
+
+	// HeartbeatInterval is how often a heartbeat is sent while the task runs. Heartbeats are disabled when zero.
+	HeartbeatInterval time.Duration
+}
+
+// NewTaskTokenHandler creates a handler for ".waitForTaskToken" Lambda tasks. It runs fn and sends its output or
+// error through the client with the task token of the payload. The handler only returns an error when the callback
+// could not be sent.
+func NewTaskTokenHandler[I, O any](client Client, fn TaskFunc[I, O], config TokenHandlerConfig) engine.Handler[TokenInput[I], struct{}] {
+	return func(ctx context.Context, in TokenInput[I]) (struct{}, error) {
+		if in.TaskToken == "" {
+			return struct{}{}, ErrMissingTaskToken
+		}
+
+		token := NewTaskToken(client, in.TaskToken, config.Config)
+
+		stop := func() {}
+
+		if config.HeartbeatInterval > 0 {
+			stop = token.KeepAlive(ctx, config.HeartbeatInterval)
+			defer stop()
+		}
+
+		out, err := fn(ctx, in.Input)
+
+		// no heartbeat may follow the callback, since Step Functions rejects heartbeats of completed tasks
+		stop()
+
+		if err != nil {
+			return struct{}{}, token.Failure(context.WithoutCancel(ctx), err)
+		}
+
+		return struct{}{}, token.Success(context.WithoutCancel(ctx), out)
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
@@ -0,0 +1,97 @@
+package refresh
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Config is the configuration of a Value.
+type Config[V any] struct {
+	// Fetch loads a fresh value.
+	Fetch func(context.Context) (V, error)
+
+	// MaxAge is how long a value is served before it is refreshed in the background.
+	MaxAge time.Duration
+
+	// OnError is called with the errors of background refreshes.
+	OnError func(error)
+
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// Value caches the result of Config.Fetch. It is fetched synchronously the first time, and once older than MaxAge
+// the stale value is served while a single refresh runs in the background. A failed background refresh is retried on
+// the next call instead of waiting for another MaxAge.
+type Value[V any] struct {
+	config Config[V]
+
+	mu         sync.Mutex
+	current    V
+	loaded     bool
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// New creates a Value for the given configuration.
+func New[V any](config Config[V]) *Value[V] {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Value[V]{config: config}
+}
+
+// Get returns the current value, fetching it synchronously when there is none yet.
+func (v *Value[V]) Get(ctx context.Context) (V, error) {
+	v.mu.Lock()
+
+	if !v.loaded {
+		v.mu.Unlock()
+		return v.Refresh(ctx)
+	}
+
+	current := v.current
+
+	if !v.refreshing && v.config.Now().Sub(v.fetchedAt) >= v.config.MaxAge {
+		v.refreshing = true
+
+		go v.refreshInBackground(context.WithoutCancel(ctx))
+	}
+
+	v.mu.Unlock()
+
+	return current, nil
+}
+
+// Refresh fetches the value synchronously and returns it.
+func (v *Value[V]) Refresh(ctx context.Context) (V, error) {
+	value, err := v.config.Fetch(ctx)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.current = value
+	v.loaded = true
+	v.fetchedAt = v.config.Now()
+
+	return value, nil
+}
+
+func (v *Value[V]) refreshInBackground(ctx context.Context) {
+	_, err := v.Refresh(ctx)
+
+	// fetchedAt is only updated on success, so a failed refresh is retried on the next call
+	v.mu.Lock()
+	v.refreshing = false
+	v.mu.Unlock()
+
+	if err != nil && v.config.OnError != nil {
+		v.config.OnError(err)
+	}
+}
@@ -0,0 +1,80 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+
+	t.Run("should serve the stale value and retry a failed refresh on the next call", func(t *testing.T) {
+		var (
+			fail    atomic.Bool
+			now     atomic.Int64
+			current atomic.Int32
+		)
+
+		errs := make(chan error, 1)
+
+		v := New(Config[int32]{
+			Fetch: func(context.Context) (int32, error) {
+				if fail.Load() {
+					return 0, errUnavailable
+				}
+
+				return current.Add(1), nil
+			},
+			MaxAge:  time.Minute,
+			OnError: func(err error) { errs <- err },
+			Now:     func() time.Time { return time.Unix(0, now.Load()) },
+		})
+
+		first, err := v.Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), first)
+
+		fail.Store(true)
+		now.Add(int64(time.Minute))
+
+		got, err := v.Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, first, got)
+		assert.ErrorIs(t, <-errs, errUnavailable)
+
+		fail.Store(false)
+
+		assert.Eventually(t, func() bool {
+			got, _ := v.Get(context.Background())
+			return got == 2
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should not cache a failed first fetch", func(t *testing.T) {
+		var calls atomic.Int32
+
+		v := New(Config[int32]{
+			Fetch: func(context.Context) (int32, error) {
+				if calls.Add(1) == 1 {
+					return 0, errUnavailable
+				}
+
+				return 7, nil
+			},
+			MaxAge: time.Minute,
+		})
+
+		_, err := v.Get(context.Background())
+		assert.ErrorIs(t, err, errUnavailable)
+
+		got, err := v.Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), got)
+	})
+}
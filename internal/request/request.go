@@ -3,6 +3,7 @@ package request
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
 type Config struct {
@@ -18,6 +19,19 @@ type Config struct {
 	Cookies     []string
 	RequestID   string
 	Stage       string
+
+	// PathParameters are the parameters of the route matched by API Gateway, exposed through r.PathValue.
+	PathParameters map[string]string
+
+	// Route is the route matched by API Gateway, the resource of REST APIs or the route key of HTTP APIs.
+	Route string
+
+	// BasePath is stripped from the start of Path, such as the stage or a custom domain base path mapping. The
+	// original path remains available through OriginalPathFrom.
+	BasePath string
+
+	// DomainName is the domain the request was received on, used as host when there is no Host header.
+	DomainName string
 }
 
 func New(ctx context.Context, cfg Config) (*http.Request, error) {
@@ -34,7 +48,62 @@ func New(ctx context.Context, cfg Config) (*http.Request, error) {
 		cookies:     cfg.Cookies,
 		requestID:   cfg.RequestID,
 		stage:       cfg.Stage,
+		pathParams:  cfg.PathParameters,
+		route:       cfg.Route,
+		basePath:    cfg.BasePath,
+		domainName:  cfg.DomainName,
 	}
 
 	return ri.toRequest(ctx)
 }
+
+type routeKey struct{}
+
+// RouteFrom returns the route matched by API Gateway for the request of ctx.
+func RouteFrom(ctx context.Context) (string, bool) {
+	route, ok := ctx.Value(routeKey{}).(string)
+	return route, ok
+}
+
+type originalPathKey struct{}
+
+// OriginalPathFrom returns the path of the request of ctx as received by API Gateway, before stripping the base path.
+func OriginalPathFrom(ctx context.Context) (string, bool) {
+	path, ok := ctx.Value(originalPathKey{}).(string)
+	return path, ok
+}
+
+// DefaultTimeoutMargin is the TimeoutMargin of the default gateway configuration.
+const DefaultTimeoutMargin = 500 * time.Millisecond
+
+// GatewayConfig is the configuration of the API gateway handlers.
+type GatewayConfig struct {
+	// StripStage removes the stage from the start of the request path, for stage prefixed URLs such as
+	// "/prod/items". The "$default" stage is never stripped.
+	StripStage bool
+
+	// BasePath is removed from the start of the request path, for custom domain base path mappings. It is ignored
+	// when StripStage is set.
+	BasePath string
+
+	// TimeoutMargin cancels the request context that long before the invocation deadline, so handlers stop their
+	// work and still have time to write a response.
+	TimeoutMargin time.Duration
+}
+
+// DefaultGatewayConfig returns the default configuration of the API gateway handlers, which keeps the path as
+// received and cancels the request context DefaultTimeoutMargin before the invocation deadline.
+func DefaultGatewayConfig() GatewayConfig {
+	return GatewayConfig{TimeoutMargin: DefaultTimeoutMargin}
+}
+
+// WithTimeoutMargin returns a copy of ctx canceled margin before its deadline, leaving the handler time to write a
+// response before the invocation times out. ctx is returned as is when it has no deadline or margin is not positive.
+func WithTimeoutMargin(ctx context.Context, margin time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || margin <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithDeadline(ctx, deadline.Add(-margin))
+}
@@ -2,9 +2,11 @@ package request
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -24,10 +26,14 @@ type requestInfo struct {
 	cookies     []string
 	requestID   string
 	stage       string
+	pathParams  map[string]string
+	route       string
+	basePath    string
+	domainName  string
 }
 
 func (ri requestInfo) toRequest(ctx context.Context) (*http.Request, error) {
-	u, err := url.Parse(ri.path)
+	u, err := url.Parse(stripBasePath(ri.path, ri.basePath))
 	if err != nil {
 		return nil, errors.Join(err, ErrParsingPathFailed)
 	}
@@ -47,9 +53,6 @@ func (ri requestInfo) toRequest(ctx context.Context) (*http.Request, error) {
 	// manually set RequestURI because NewRequest is for clients and req.RequestURI is for servers
 	req.RequestURI = u.RequestURI()
 
-	// remote addr
-	req.RemoteAddr = ri.sourceIP
-
 	// headers
 	for k, v := range ri.headers {
 		req.Header.Set(k, v)
@@ -76,7 +79,18 @@ func (ri requestInfo) toRequest(ctx context.Context) (*http.Request, error) {
 	req.Header.Set("X-Request-Id", ri.requestID)
 	req.Header.Set("X-Stage", ri.stage)
 
+	// path parameters, a mux pattern wildcard with the same name takes precedence
+	for k, v := range ri.pathParams {
+		req.SetPathValue(k, v)
+	}
+
 	// custom context values
+	ctx = context.WithValue(ctx, originalPathKey{}, ri.path)
+
+	if ri.route != "" {
+		ctx = context.WithValue(ctx, routeKey{}, ri.route)
+	}
+
 	//revive:disable-next-line:context-keys-type
 	req = req.WithContext(context.WithValue(ctx, "httpRequestContext", ri.context))
 
@@ -85,13 +99,103 @@ func (ri requestInfo) toRequest(ctx context.Context) (*http.Request, error) {
 		req.Header.Set("X-Amzn-Trace-Id", fmt.Sprintf("%v", traceID))
 	}
 
-	// host
-	req.URL.Host = req.Header.Get("Host")
-	req.Host = req.URL.Host
+	ri.setForwarded(req)
 
 	return req, nil
 }
 
+// setForwarded sets the host, TLS state and remote address of req from the forwarding headers added by API Gateway.
+func (ri requestInfo) setForwarded(req *http.Request) {
+	// multi value headers of HTTP APIs come split by commas
+	if values := req.Header.Values("X-Forwarded-For"); len(values) > 1 {
+		hops := make([]string, 0, len(values))
+		for _, v := range values {
+			hops = append(hops, strings.TrimSpace(v))
+		}
+
+		req.Header.Set("X-Forwarded-For", strings.Join(hops, ", "))
+	}
+
+	proto := strings.ToLower(strings.TrimSpace(req.Header.Get("X-Forwarded-Proto")))
+	if proto == "" && ri.domainName != "" {
+		// API Gateway endpoints are only served over HTTPS
+		proto = "https"
+	}
+
+	host := req.Header.Get("Host")
+	if host == "" {
+		host = ri.domainName
+	}
+
+	port := strings.TrimSpace(req.Header.Get("X-Forwarded-Port"))
+	if _, _, err := net.SplitHostPort(host); err != nil && host != "" && port != "" && port != defaultPort(proto) {
+		host = net.JoinHostPort(host, port)
+	}
+
+	req.URL.Host = host
+	req.Host = host
+
+	if proto == "https" {
+		req.TLS = &tls.ConnectionState{
+			HandshakeComplete: true,
+			ServerName:        hostname(host),
+		}
+	}
+
+	// API Gateway does not forward the client port
+	if ri.sourceIP != "" {
+		req.RemoteAddr = net.JoinHostPort(ri.sourceIP, "0")
+	}
+}
+
+func defaultPort(proto string) string {
+	if proto == "https" {
+		return "443"
+	}
+
+	return "80"
+}
+
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+
+	return host
+}
+
+// BasePath returns the prefix to strip from the request path of the given stage: the stage itself when stripStage is
+// set, except for the "$default" stage, or basePath otherwise.
+func BasePath(stripStage bool, basePath, stage string) string {
+	if stripStage {
+		if stage == "$default" {
+			return ""
+		}
+
+		return stage
+	}
+
+	return basePath
+}
+
+// stripBasePath removes the base path from the start of path when it matches whole segments.
+func stripBasePath(path, basePath string) string {
+	basePath = "/" + strings.Trim(basePath, "/")
+	if basePath == "/" {
+		return path
+	}
+
+	if path == basePath {
+		return "/"
+	}
+
+	if rest, ok := strings.CutPrefix(path, basePath); ok && strings.HasPrefix(rest, "/") {
+		return rest
+	}
+
+	return path
+}
+
 func (ri requestInfo) decodeBody() (string, error) {
 	if ri.isBase64 {
 		b, errDecode := base64.StdEncoding.DecodeString(ri.body)
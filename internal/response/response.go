@@ -2,12 +2,19 @@ package response
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
+	"fmt"
 	"mime"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
+var ErrWriteDeadlineExceeded = fmt.Errorf("response: write deadline exceeded: %w", os.ErrDeadlineExceeded)
+
 type Out interface {
 	SetStatusCode(int)
 	SetHeaders(map[string]string)
@@ -19,20 +26,32 @@ type Out interface {
 
 // Writer implements the http.ResponseWriter interface
 // in order to support the API Gateway Lambda HTTP "protocol".
+// It supports http.NewResponseController for flushing and write deadlines.
 type Writer[R Out] struct {
 	out           R
 	buf           bytes.Buffer
 	header        http.Header
+	sent          http.Header
+	status        int
 	wroteHeader   bool
+	head          bool
 	closeNotifyCh chan bool
+	deadline      time.Time
+	stopNotify    func() bool
 }
 
-// New returns a new response writer to capture http output.
-func New[R Out](out R) *Writer[R] {
-	return &Writer[R]{
+// New returns a new response writer to capture the http output of the given request. CloseNotify is signaled when
+// the request context is done or the response ends.
+func New[R Out](r *http.Request, out R) *Writer[R] {
+	w := &Writer[R]{
 		out:           out,
+		head:          r.Method == http.MethodHead,
 		closeNotifyCh: make(chan bool, 1),
 	}
+
+	w.stopNotify = context.AfterFunc(r.Context(), w.notifyClose)
+
+	return w
 }
 
 // Header implementation.
@@ -44,31 +63,100 @@ func (w *Writer[R]) Header() http.Header {
 	return w.header
 }
 
-// Write implementation.
+// Write implementation. The body of HEAD requests is only used to compute the Content-Length.
 func (w *Writer[R]) Write(b []byte) (int, error) {
+	if !w.deadline.IsZero() && !time.Now().Before(w.deadline) {
+		return 0, ErrWriteDeadlineExceeded
+	}
+
 	if !w.wroteHeader {
 		w.WriteHeader(http.StatusOK)
 	}
 
+	if !bodyAllowed(w.status) {
+		return 0, http.ErrBodyNotAllowed
+	}
+
 	return w.buf.Write(b)
 }
 
-// WriteHeader implementation.
+// WriteHeader implementation. Informational 1xx statuses can't be sent through API Gateway, so they are ignored.
+// Header changes after the first call are ignored, except for trailers.
 func (w *Writer[R]) WriteHeader(status int) {
-	if w.wroteHeader {
+	if w.wroteHeader || (status >= 100 && status <= 199) {
 		return
 	}
 
-	if w.Header().Get("Content-Type") == "" {
-		w.Header().Set("Content-Type", "text/plain; charset=utf8")
+	w.status = status
+	w.sent = w.Header().Clone()
+	w.wroteHeader = true
+}
+
+// Flush implements http.Flusher. The response is returned to API Gateway as a whole when the handler ends, so
+// flushing only commits the status and headers.
+func (w *Writer[R]) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
 	}
+}
 
-	w.out.SetStatusCode(status)
+// SetWriteDeadline makes writes after the deadline fail with ErrWriteDeadlineExceeded, which matches
+// os.ErrDeadlineExceeded. A zero value means no deadline. It is used by http.ResponseController.
+func (w *Writer[R]) SetWriteDeadline(deadline time.Time) error {
+	w.deadline = deadline
+	return nil
+}
+
+// CloseNotify notify when the response is closed or the invocation context is done.
+//
+// Deprecated: use the request context instead, which is canceled with the invocation context.
+func (w *Writer[R]) CloseNotify() <-chan bool {
+	return w.closeNotifyCh
+}
+
+func (w *Writer[R]) notifyClose() {
+	select {
+	case w.closeNotifyCh <- true:
+	default:
+	}
+}
+
+// End the request.
+func (w *Writer[R]) End() R {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	header := w.sent
+	body := w.buf.Bytes()
+
+	w.foldTrailers(header)
+
+	if bodyAllowed(w.status) && (len(body) > 0 || !w.head) {
+		// like net/http, an explicitly nil Content-Type disables sniffing
+		if _, ok := header["Content-Type"]; !ok && len(body) > 0 {
+			header.Set("Content-Type", http.DetectContentType(body))
+		}
+
+		if header.Get("Content-Length") == "" && header.Get("Transfer-Encoding") == "" {
+			header.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+	}
+
+	if w.head {
+		body = nil
+	}
+
+	w.out.SetStatusCode(w.status)
+
+	// see https://aws.amazon.com/blogs/compute/simply-serverless-using-aws-lambda-to-expose-custom-cookies-with-api-gateway/
+	cookies := header["Set-Cookie"]
+	header.Del("Set-Cookie")
 
 	h := make(map[string]string)
 	mvh := make(map[string][]string)
 
-	for k, v := range w.Header() {
+	for k, v := range header {
 		if len(v) == 1 {
 			h[k] = v[0]
 		} else if len(v) > 1 {
@@ -79,36 +167,52 @@ func (w *Writer[R]) WriteHeader(status int) {
 	w.out.SetHeaders(h)
 	w.out.SetMultiValueHeaders(mvh)
 
-	w.wroteHeader = true
-}
-
-// CloseNotify notify when the response is closed
-func (w *Writer[R]) CloseNotify() <-chan bool {
-	return w.closeNotifyCh
-}
-
-// End the request.
-func (w *Writer[R]) End() R {
-	isBin := isBinary(w.header)
+	isBin := len(body) > 0 && isBinary(header)
 
 	w.out.SetIsBase64Encoded(isBin)
 
 	if isBin {
-		w.out.SetBody(base64.StdEncoding.EncodeToString(w.buf.Bytes()))
+		w.out.SetBody(base64.StdEncoding.EncodeToString(body))
 	} else {
-		w.out.SetBody(w.buf.String())
+		w.out.SetBody(string(body))
 	}
 
-	// see https://aws.amazon.com/blogs/compute/simply-serverless-using-aws-lambda-to-expose-custom-cookies-with-api-gateway/
-	w.out.SetCookies(w.header["Set-Cookie"])
-	w.header.Del("Set-Cookie")
+	w.out.SetCookies(cookies)
 
 	// notify end
-	w.closeNotifyCh <- true
+	w.stopNotify()
+	w.notifyClose()
 
 	return w.out
 }
 
+// foldTrailers adds to header the trailers declared in its Trailer header, or set with the http.TrailerPrefix, since
+// API Gateway responses can't carry trailers.
+func (w *Writer[R]) foldTrailers(header http.Header) {
+	for _, v := range header.Values("Trailer") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+
+			if values, ok := w.header[name]; ok {
+				header[name] = values
+			}
+		}
+	}
+
+	header.Del("Trailer")
+
+	for k, values := range w.header {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+}
+
+// bodyAllowed reports whether a response with the given status may have a body.
+func bodyAllowed(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified
+}
+
 // isBinary returns true if the response represents binary.
 func isBinary(h http.Header) bool {
 	switch {
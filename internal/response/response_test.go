@@ -0,0 +1,172 @@
+package response
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testOut struct {
+	status    int
+	headers   map[string]string
+	mvHeaders map[string][]string
+	body      string
+	base64    bool
+	cookies   []string
+}
+
+func (o *testOut) SetStatusCode(status int)                   { o.status = status }
+func (o *testOut) SetHeaders(h map[string]string)             { o.headers = h }
+func (o *testOut) SetMultiValueHeaders(h map[string][]string) { o.mvHeaders = h }
+func (o *testOut) SetBody(body string)                        { o.body = body }
+func (o *testOut) SetIsBase64Encoded(b bool)                  { o.base64 = b }
+func (o *testOut) SetCookies(cookies []string)                { o.cookies = cookies }
+
+func newRequest(ctx context.Context, method string) *http.Request {
+	req, _ := http.NewRequestWithContext(ctx, method, "/", nil)
+	return req
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("should flush through the response controller", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+		err := http.NewResponseController(w).Flush()
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.End().status)
+	})
+
+	t.Run("should fail writes after the write deadline", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+		rc := http.NewResponseController(w)
+
+		assert.NoError(t, rc.SetWriteDeadline(time.Now().Add(time.Hour)))
+
+		_, err := w.Write([]byte("a"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, rc.SetWriteDeadline(time.Now().Add(-time.Second)))
+
+		_, err = w.Write([]byte("b"))
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+		assert.Equal(t, "a", w.End().body)
+	})
+
+	t.Run("should signal close notify when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		w := New(newRequest(ctx, http.MethodGet), new(testOut))
+		cancel()
+
+		select {
+		case <-w.CloseNotify():
+		case <-time.After(time.Second):
+			t.Fatal("close notify was not signaled")
+		}
+
+		w.End()
+	})
+	t.Run("should sniff the content type and set the content length", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+		_, _ = w.Write([]byte("<html><body>hello</body></html>"))
+
+		out := w.End()
+
+		assert.Equal(t, http.StatusOK, out.status)
+		assert.Equal(t, "text/html; charset=utf-8", out.headers["Content-Type"])
+		assert.Equal(t, "31", out.headers["Content-Length"])
+		assert.False(t, out.base64)
+	})
+
+	t.Run("should encode sniffed binary content", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+		_, _ = w.Write([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00})
+
+		out := w.End()
+
+		assert.Equal(t, "image/png", out.headers["Content-Type"])
+		assert.True(t, out.base64)
+	})
+
+	t.Run("should drop the body of head requests", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodHead), new(testOut))
+
+		w.Header().Set("Content-Type", "application/json")
+		n, err := w.Write([]byte(`{"id":1}`))
+
+		out := w.End()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, n)
+		assert.Empty(t, out.body)
+		assert.Equal(t, "8", out.headers["Content-Length"])
+	})
+
+	t.Run("should ignore informational statuses", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusCreated)
+
+		out := w.End()
+
+		assert.Equal(t, http.StatusCreated, out.status)
+		assert.Equal(t, "0", out.headers["Content-Length"])
+		assert.Empty(t, out.headers["Content-Type"])
+	})
+
+	t.Run("should reject bodies of no content responses", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+		w.WriteHeader(http.StatusNoContent)
+		_, err := w.Write([]byte("a"))
+
+		out := w.End()
+
+		assert.ErrorIs(t, err, http.ErrBodyNotAllowed)
+		assert.Empty(t, out.headers["Content-Length"])
+	})
+
+	t.Run("should fold trailers into headers", func(t *testing.T) {
+		w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("hello"))
+
+		w.Header().Set("X-Checksum", "abc")
+		w.Header().Set(http.TrailerPrefix+"X-Duration", "12ms")
+		w.Header().Set("X-Ignored", "late")
+
+		out := w.End()
+
+		assert.Equal(t, "abc", out.headers["X-Checksum"])
+		assert.Equal(t, "12ms", out.headers["X-Duration"])
+		assert.NotContains(t, out.headers, "Trailer")
+		assert.NotContains(t, out.headers, "X-Ignored")
+	})
+
+	t.Run("should send cookies apart from the headers", func(t *testing.T) {
+		for _, cookies := range [][]string{{"a=1"}, {"a=1", "b=2"}} {
+			w := New(newRequest(context.Background(), http.MethodGet), new(testOut))
+
+			for _, c := range cookies {
+				w.Header().Add("Set-Cookie", c)
+			}
+
+			out := w.End()
+
+			assert.Equal(t, cookies, out.cookies)
+			assert.NotContains(t, out.headers, "Set-Cookie")
+			assert.NotContains(t, out.mvHeaders, "Set-Cookie")
+		}
+	})
+}
@@ -0,0 +1,56 @@
+// Package logger gives handlers access to a request scoped *slog.Logger carried by the invocation context.
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx carrying the given logger.
+func NewContext(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// From returns the logger stored in ctx by decorators.Logger, or slog.Default() when there is none.
+func From(ctx context.Context) *slog.Logger {
+	if log, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return log
+	}
+
+	return slog.Default()
+}
+
+// With returns a copy of ctx carrying the logger of ctx with the given attributes added.
+func With(ctx context.Context, args ...any) context.Context {
+	return NewContext(ctx, From(ctx).With(args...))
+}
+
+// WithLevel returns a copy of ctx whose logger also emits records at or above level, even when its handler is
+// configured with a higher minimum level. It is used to escalate a single invocation to debug logs.
+func WithLevel(ctx context.Context, level slog.Leveler) context.Context {
+	base := From(ctx).Handler()
+	if h, ok := base.(*levelHandler); ok {
+		base = h.Handler
+	}
+
+	return NewContext(ctx, slog.New(&levelHandler{Handler: base, level: level}))
+}
+
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() || h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
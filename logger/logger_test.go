@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrom(t *testing.T) {
+	t.Run("should return the default logger when ctx has none", func(t *testing.T) {
+		assert.Same(t, slog.Default(), From(context.Background()))
+	})
+
+	t.Run("should return the logger stored in ctx with added attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		ctx := NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, nil)))
+		ctx = With(ctx, "method", "GET")
+
+		From(ctx).Info("hello")
+
+		assert.Contains(t, buf.String(), "msg=hello method=GET")
+	})
+}
+
+func TestWithLevel(t *testing.T) {
+	t.Run("should emit records below the handler level", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		ctx := NewContext(context.Background(), slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+		From(ctx).Debug("hidden")
+		From(WithLevel(ctx, slog.LevelDebug)).With("k", "v").Debug("shown")
+
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), "level=DEBUG msg=shown k=v")
+	})
+}
@@ -0,0 +1,299 @@
+// Package cache provides an HTTP middleware caching the responses of idempotent requests and answering conditional
+// requests, for handlers served through the apigatewayv1 and apigatewayv2 adapters.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheableStatus lists the status codes whose responses are stored.
+var cacheableStatus = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusGone,
+}
+
+// Config is the configuration for the cache middleware.
+type Config struct {
+	// Store keeps the cached responses. Defaults to an LRU store of DefaultCapacity entries.
+	Store Store
+
+	// Key returns the cache key of a request. Defaults to the host and request URI.
+	Key func(*http.Request) string
+
+	// DefaultTTL is how long responses without a max-age or s-maxage directive are cached. They are not cached when
+	// zero.
+	DefaultTTL time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// DefaultConfig returns the default configuration for the cache middleware.
+func DefaultConfig() Config {
+	return Config{Store: NewLRU(DefaultCapacity)}
+}
+
+// Middleware caches the responses of GET and HEAD requests according to their Cache-Control header, and answers
+// requests whose If-None-Match or If-Modified-Since headers match the response with 304 Not Modified. Responses
+// marked no-store or private, setting cookies or varying on every header are never stored, nor are responses to
+// requests with an Authorization header unless marked public, s-maxage or must-revalidate. Store errors are ignored,
+// letting the handler serve the request.
+func Middleware(config Config) func(http.Handler) http.Handler {
+	if config.Store == nil {
+		config.Store = NewLRU(DefaultCapacity)
+	}
+
+	if config.Key == nil {
+		config.Key = defaultKey
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := config.Key(r)
+			now := config.Now()
+			reqDirectives := parseCacheControl(r.Header.Get("Cache-Control"))
+
+			if !reqDirectives.has("no-cache") && !reqDirectives.has("no-store") {
+				if entry, ok := lookup(r.Context(), config.Store, key, r, now); ok {
+					entry.Header.Set("Age", strconv.Itoa(int(now.Sub(entry.StoredAt).Seconds())))
+					entry.Header.Set("X-Cache", "HIT")
+					serve(w, r, entry.Status, entry.Header, entry.Body)
+
+					return
+				}
+			}
+
+			rec := &recorder{header: make(http.Header)}
+			next.ServeHTTP(rec, r)
+
+			status := rec.statusCode()
+
+			if r.Method == http.MethodGet && !reqDirectives.has("no-store") {
+				if ttl, ok := lifetime(r, status, rec.header, config.DefaultTTL); ok {
+					_ = config.Store.Set(r.Context(), key, Entry{
+						Status:   status,
+						Header:   rec.header.Clone(),
+						Body:     rec.body.Bytes(),
+						Vary:     varyValues(rec.header, r),
+						StoredAt: now,
+						Expires:  now.Add(ttl),
+					})
+				}
+			}
+
+			rec.header.Set("X-Cache", "MISS")
+			serve(w, r, status, rec.header, rec.body.Bytes())
+		})
+	}
+}
+
+func defaultKey(r *http.Request) string {
+	return r.Host + r.URL.RequestURI()
+}
+
+// lookup returns the fresh entry of key matching the varying headers of r.
+func lookup(ctx context.Context, store Store, key string, r *http.Request, now time.Time) (Entry, bool) {
+	entry, ok, err := store.Get(ctx, key)
+	if err != nil || !ok || !now.Before(entry.Expires) {
+		return Entry{}, false
+	}
+
+	for name, value := range entry.Vary {
+		if r.Header.Get(name) != value {
+			return Entry{}, false
+		}
+	}
+
+	entry.Header = entry.Header.Clone()
+
+	return entry, true
+}
+
+// lifetime returns how long the response to r may be cached, reporting whether it may be cached at all.
+func lifetime(r *http.Request, status int, header http.Header, defaultTTL time.Duration) (time.Duration, bool) {
+	if !slices.Contains(cacheableStatus, status) || header.Get("Set-Cookie") != "" || header.Get("Vary") == "*" {
+		return 0, false
+	}
+
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if directives.has("no-store") || directives.has("private") || directives.has("no-cache") {
+		return 0, false
+	}
+
+	// responses to authorized requests are only shared when explicitly allowed, see RFC 9111 section 3.5
+	if r.Header.Get("Authorization") != "" &&
+		!directives.has("public") && !directives.has("s-maxage") && !directives.has("must-revalidate") {
+		return 0, false
+	}
+
+	for _, name := range []string{"s-maxage", "max-age"} {
+		if v, ok := directives[name]; ok {
+			seconds, err := strconv.Atoi(v)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return defaultTTL, defaultTTL > 0
+}
+
+func varyValues(header http.Header, r *http.Request) map[string]string {
+	var values map[string]string
+
+	for _, v := range header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			if values == nil {
+				values = make(map[string]string)
+			}
+
+			values[name] = r.Header.Get(name)
+		}
+	}
+
+	return values
+}
+
+// serve writes the response, replacing it with 304 Not Modified when the conditional headers of r match.
+func serve(w http.ResponseWriter, r *http.Request, status int, header http.Header, body []byte) {
+	dst := w.Header()
+
+	if status == http.StatusOK && notModified(r, header) {
+		for _, name := range []string{"Cache-Control", "Content-Location", "Date", "ETag", "Expires", "Last-Modified", "Vary", "Age", "X-Cache"} {
+			if values := header.Values(name); len(values) > 0 {
+				dst[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	for name, values := range header {
+		dst[name] = values
+	}
+
+	w.WriteHeader(status)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// notModified evaluates the If-None-Match and If-Modified-Since headers of r against the response header.
+func notModified(r *http.Request, header http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag := header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || weakTag(candidate) == weakTag(etag) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !modified.Truncate(time.Second).After(ims)
+}
+
+func weakTag(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
+
+type directives map[string]string
+
+func parseCacheControl(v string) directives {
+	d := make(directives)
+
+	for _, part := range strings.Split(v, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+
+		d[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+
+	return d
+}
+
+func (d directives) has(name string) bool {
+	_, ok := d[name]
+	return ok
+}
+
+// recorder buffers the response of the wrapped handler.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	return r.body.Write(b)
+}
+
+func (r *recorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Drafteame/engine/handlers/apigatewayv2"
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newCachedHandler(calls *int, cacheControl string, clock *fakeClock) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*calls++
+
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	config := DefaultConfig()
+	config.Now = clock.Now
+
+	return Middleware(config)(handler)
+}
+
+func get(h http.Handler, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should serve cached responses until they expire", func(t *testing.T) {
+		calls := 0
+		clock := &fakeClock{now: time.Now()}
+		h := newCachedHandler(&calls, "public, max-age=60", clock)
+
+		first := get(h)
+		assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+
+		clock.now = clock.now.Add(30 * time.Second)
+
+		second := get(h)
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+		assert.Equal(t, "30", second.Header().Get("Age"))
+		assert.Equal(t, `{"id":1}`, second.Body.String())
+		assert.Equal(t, 1, calls)
+
+		clock.now = clock.now.Add(30 * time.Second)
+
+		get(h)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should answer conditional requests with not modified", func(t *testing.T) {
+		calls := 0
+		h := newCachedHandler(&calls, "no-store", &fakeClock{now: time.Now()})
+
+		rec := get(h, "If-None-Match", `W/"v0", W/"v1"`)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, `"v1"`, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Body.String())
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("should not store private responses", func(t *testing.T) {
+		calls := 0
+		h := newCachedHandler(&calls, "private, max-age=60", &fakeClock{now: time.Now()})
+
+		get(h)
+		get(h)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should not share responses to authorized requests", func(t *testing.T) {
+		calls := 0
+		h := newCachedHandler(&calls, "max-age=60", &fakeClock{now: time.Now()})
+
+		get(h, "Authorization", "Bearer token")
+
+		rec := get(h)
+
+		assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should share responses to authorized requests marked public", func(t *testing.T) {
+		calls := 0
+		h := newCachedHandler(&calls, "public, max-age=60", &fakeClock{now: time.Now()})
+
+		get(h, "Authorization", "Bearer token")
+
+		assert.Equal(t, "HIT", get(h).Header().Get("X-Cache"))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should bypass the cache when the request asks for it", func(t *testing.T) {
+		calls := 0
+		h := newCachedHandler(&calls, "max-age=60", &fakeClock{now: time.Now()})
+
+		get(h)
+		get(h, "Cache-Control", "no-cache")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should honor if-modified-since", func(t *testing.T) {
+		modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		h := Middleware(DefaultConfig())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+			_, _ = w.Write([]byte("hello"))
+		}))
+
+		assert.Equal(t, http.StatusNotModified, get(h, "If-Modified-Since", modified.Format(http.TimeFormat)).Code)
+		assert.Equal(t, http.StatusOK, get(h, "If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat)).Code)
+	})
+
+	t.Run("should key responses by their vary headers", func(t *testing.T) {
+		calls := 0
+
+		h := Middleware(DefaultConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+			_, _ = w.Write([]byte(r.Header.Get("Accept-Language")))
+		}))
+
+		get(h, "Accept-Language", "en")
+		assert.Equal(t, "en", get(h, "Accept-Language", "en").Body.String())
+		assert.Equal(t, "es", get(h, "Accept-Language", "es").Body.String())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should cache responses across gateway invocations", func(t *testing.T) {
+		calls := 0
+		h := newCachedHandler(&calls, "max-age=60", &fakeClock{now: time.Now()})
+
+		handler := apigatewayv2.NewHandler(h)
+
+		evt := apigatewayv2.HTTPRequest{
+			RawPath: "/items/1",
+			RequestContext: apigatewayv2.HTTPRequestContext{
+				HTTP: apigatewayv2.HTTPRequestContextHTTPDescription{Method: http.MethodGet, Path: "/items/1"},
+			},
+		}
+
+		_, err := testengine.New(context.Background(), evt, handler).Run()
+		require.NoError(t, err)
+
+		evt.Headers = map[string]string{"if-none-match": `"v1"`}
+
+		res, err := testengine.New(context.Background(), evt, handler).Run()
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestLRU(t *testing.T) {
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewLRU(2)
+
+		_ = store.Set(ctx, "a", Entry{Status: 1})
+		_ = store.Set(ctx, "b", Entry{Status: 2})
+		_, _, _ = store.Get(ctx, "a")
+		_ = store.Set(ctx, "c", Entry{Status: 3})
+
+		_, ok, _ := store.Get(ctx, "b")
+		assert.False(t, ok)
+
+		entry, ok, _ := store.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, entry.Status)
+		assert.Equal(t, 2, store.Len())
+	})
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the number of responses kept by the default LRU store.
+const DefaultCapacity = 1000
+
+// Entry is a cached response.
+type Entry struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Vary holds the values of the request headers named by the Vary response header, keyed by canonical name.
+	Vary map[string]string
+
+	StoredAt time.Time
+	Expires  time.Time
+}
+
+// Store keeps cached responses. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the entry stored under key, reporting whether there is one.
+	Get(ctx context.Context, key string) (Entry, bool, error)
+
+	// Set stores the entry under key, replacing any previous one.
+	Set(ctx context.Context, key string, entry Entry) error
+}
+
+// LRU is an in-memory Store evicting the least recently used entries. Its contents persist across the warm
+// invocations of an execution environment.
+type LRU struct {
+	capacity int
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry Entry
+}
+
+// NewLRU returns an LRU store holding up to capacity entries.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Store.
+func (s *LRU) Get(_ context.Context, key string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+
+	s.order.MoveToFront(el)
+
+	return el.Value.(*lruItem).entry, true, nil
+}
+
+// Set implements Store.
+func (s *LRU) Set(_ context.Context, key string, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		s.order.MoveToFront(el)
+
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&lruItem{key: key, entry: entry})
+
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruItem).key)
+	}
+
+	return nil
+}
+
+// Len returns the number of stored entries.
+func (s *LRU) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
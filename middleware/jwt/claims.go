@@ -0,0 +1,154 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type claimsKey struct{}
+
+// Claims holds the claims of a verified token. Claims verified by this package keep their JSON types, while claims
+// forwarded by an API Gateway authorizer are strings.
+type Claims map[string]any
+
+// WithClaims returns a copy of ctx carrying the given claims.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFrom returns the claims stored in ctx, either by the Middleware or by the API Gateway handlers when the
+// request was authorized by a JWT or Cognito authorizer.
+func ClaimsFrom(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// FromGateway builds claims from the string claims and scopes forwarded by an API Gateway authorizer.
+func FromGateway(claims map[string]string, scopes []string) Claims {
+	c := make(Claims, len(claims)+1)
+
+	for k, v := range claims {
+		c[k] = v
+	}
+
+	if _, ok := c["scope"]; !ok && len(scopes) > 0 {
+		c["scope"] = strings.Join(scopes, " ")
+	}
+
+	return c
+}
+
+// String returns the named claim as a string.
+func (c Claims) String(name string) string {
+	switch v := c[name].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// Subject returns the "sub" claim.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Issuer returns the "iss" claim.
+func (c Claims) Issuer() string {
+	return c.String("iss")
+}
+
+// Audience returns the "aud" claim, which can be either a single string or a list.
+func (c Claims) Audience() []string {
+	return c.list("aud", false)
+}
+
+// Scopes returns the scopes granted by the "scope" claim, or by the "scp" claim as used by some providers.
+func (c Claims) Scopes() []string {
+	if _, ok := c["scope"]; ok {
+		return c.list("scope", true)
+	}
+
+	return c.list("scp", true)
+}
+
+// HasScopes reports whether every given scope was granted.
+func (c Claims) HasScopes(scopes ...string) bool {
+	granted := c.Scopes()
+
+	for _, s := range scopes {
+		if !slices.Contains(granted, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExpiresAt returns the "exp" claim.
+func (c Claims) ExpiresAt() (time.Time, bool) {
+	return c.time("exp")
+}
+
+// NotBefore returns the "nbf" claim.
+func (c Claims) NotBefore() (time.Time, bool) {
+	return c.time("nbf")
+}
+
+// Decode decodes the claims into v using the "encoding/json" rules.
+func (c Claims) Decode(v any) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+func (c Claims) list(name string, split bool) []string {
+	switch v := c[name].(type) {
+	case string:
+		if split {
+			return strings.Fields(v)
+		}
+
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+
+		return out
+	case []string:
+		return v
+	default:
+		return nil
+	}
+}
+
+func (c Claims) time(name string) (time.Time, bool) {
+	raw := c.String(name)
+	if raw == "" {
+		return time.Time{}, false
+	}
+
+	secs, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(secs), 0), true
+}
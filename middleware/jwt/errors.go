@@ -0,0 +1,19 @@
+package jwt
+
+import "errors"
+
+var (
+	ErrMissingToken         = errors.New("jwt: missing bearer token")
+	ErrMalformedToken       = errors.New("jwt: malformed token")
+	ErrUnsupportedAlgorithm = errors.New("jwt: unsupported algorithm")
+	ErrKeyNotFound          = errors.New("jwt: signing key not found")
+	ErrInvalidKey           = errors.New("jwt: invalid key")
+	ErrInvalidSignature     = errors.New("jwt: invalid signature")
+	ErrTokenExpired         = errors.New("jwt: token expired")
+	ErrTokenNotYetValid     = errors.New("jwt: token not yet valid")
+	ErrInvalidIssuer        = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience      = errors.New("jwt: invalid audience")
+	ErrInsufficientScope    = errors.New("jwt: insufficient scope")
+	ErrFetchingKeys         = errors.New("jwt: fetching keys failed")
+	ErrMissingKeySource     = errors.New("jwt: missing key source")
+)
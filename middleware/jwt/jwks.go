@@ -0,0 +1,266 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWK is a JSON Web Key as described by RFC 7517. Only public RSA, EC and OKP (Ed25519) keys are supported.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySource provides the key set used to verify token signatures.
+type KeySource interface {
+	Keys(ctx context.Context) (JWKS, error)
+}
+
+// KeySourceFunc adapts a function to the KeySource interface.
+type KeySourceFunc func(ctx context.Context) (JWKS, error)
+
+// Keys implements KeySource.
+func (f KeySourceFunc) Keys(ctx context.Context) (JWKS, error) {
+	return f(ctx)
+}
+
+// StaticKeySource returns a KeySource that always provides the given key set.
+func StaticKeySource(jwks JWKS) KeySource {
+	return KeySourceFunc(func(context.Context) (JWKS, error) {
+		return jwks, nil
+	})
+}
+
+// HTTPKeySource returns a KeySource that fetches the key set from the given URL, usually the "jwks_uri" of the
+// issuer. If client is nil, http.DefaultClient is used.
+func HTTPKeySource(url string, client *http.Client) KeySource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return KeySourceFunc(func(ctx context.Context) (JWKS, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return JWKS{}, err
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return JWKS{}, err
+		}
+
+		defer func() { _ = res.Body.Close() }()
+
+		if res.StatusCode != http.StatusOK {
+			return JWKS{}, fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+		}
+
+		var jwks JWKS
+		if err := json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+			return JWKS{}, err
+		}
+
+		return jwks, nil
+	})
+}
+
+// PublicKey parses the key material.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, errN := decodeBigInt(k.N)
+		e, errE := decodeBigInt(k.E)
+
+		if errN != nil || errE != nil || !e.IsInt64() {
+			return nil, ErrInvalidKey
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+
+		x, errX := decodeBigInt(k.X)
+		y, errY := decodeBigInt(k.Y)
+
+		if errX != nil || errY != nil || !curve.IsOnCurve(x, y) {
+			return nil, ErrInvalidKey
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, ErrInvalidKey
+	}
+}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+type cachedKey struct {
+	jwk JWK
+	key crypto.PublicKey
+}
+
+// keyCache keeps the parsed keys of a KeySource for the lifetime of the execution environment. Keys are refreshed
+// when the TTL expires, or when a token references an unknown key id, at most once per minRefresh. When a refresh
+// fails, the cached keys are still served and the next attempt is delayed by a backoff that doubles up to the TTL.
+type keyCache struct {
+	source     KeySource
+	ttl        time.Duration
+	minRefresh time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      []cachedKey
+	fetchedAt time.Time
+	failures  int
+	retryAt   time.Time
+}
+
+func newKeyCache(source KeySource, ttl time.Duration, now func() time.Time) *keyCache {
+	return &keyCache{
+		source:     source,
+		ttl:        ttl,
+		minRefresh: time.Minute,
+		now:        now,
+	}
+}
+
+func (c *keyCache) key(ctx context.Context, kid string) (cachedKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fetchedAt.IsZero() {
+		if err := c.refresh(ctx); err != nil {
+			return cachedKey{}, err
+		}
+	} else if c.now().Sub(c.fetchedAt) > c.ttl && c.canRetry() {
+		// a failed refresh keeps the stale keys, which are better than failing every request
+		_ = c.refresh(ctx)
+	}
+
+	if k, ok := c.lookup(kid); ok {
+		return k, nil
+	}
+
+	if c.now().Sub(c.fetchedAt) < c.minRefresh || !c.canRetry() {
+		return cachedKey{}, ErrKeyNotFound
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		return cachedKey{}, err
+	}
+
+	if k, ok := c.lookup(kid); ok {
+		return k, nil
+	}
+
+	return cachedKey{}, ErrKeyNotFound
+}
+
+// canRetry reports whether the backoff of the last failed refresh is over.
+func (c *keyCache) canRetry() bool {
+	return !c.now().Before(c.retryAt)
+}
+
+func (c *keyCache) lookup(kid string) (cachedKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		return c.keys[0], true
+	}
+
+	for _, k := range c.keys {
+		if k.jwk.Kid == kid {
+			return k, true
+		}
+	}
+
+	return cachedKey{}, false
+}
+
+// backoff returns the delay after the last failed refresh, starting at minRefresh and doubling up to the TTL.
+func (c *keyCache) backoff() time.Duration {
+	d := c.minRefresh
+
+	for i := 1; i < c.failures && d < c.ttl; i++ {
+		d *= 2
+	}
+
+	return min(d, c.ttl)
+}
+
+func (c *keyCache) refresh(ctx context.Context) error {
+	jwks, err := c.source.Keys(ctx)
+	if err != nil {
+		c.failures++
+		c.retryAt = c.now().Add(c.backoff())
+
+		return errors.Join(err, ErrFetchingKeys)
+	}
+
+	keys := make([]cachedKey, 0, len(jwks.Keys))
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys = append(keys, cachedKey{jwk: jwk, key: pub})
+	}
+
+	c.keys = keys
+	c.fetchedAt = c.now()
+	c.failures = 0
+	c.retryAt = time.Time{}
+
+	return nil
+}
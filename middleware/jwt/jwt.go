@@ -0,0 +1,192 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config is the configuration for the JWT middleware.
+type Config struct {
+	// Keys provides the key set used to verify signatures. It is required.
+	Keys KeySource
+
+	// CacheTTL is how long fetched keys are kept before fetching them again. Defaults to one hour.
+	CacheTTL time.Duration
+
+	// Issuer is the expected "iss" claim. It is not checked when empty.
+	Issuer string
+
+	// Audience lists the accepted "aud" claim values; the token must contain at least one of them. It is not checked
+	// when empty.
+	Audience []string
+
+	// Scopes lists the scopes the token must grant.
+	Scopes []string
+
+	// Algorithms lists the accepted signing algorithms. Defaults to DefaultAlgorithms.
+	Algorithms []string
+
+	// Leeway is the clock skew tolerated when checking the "exp" and "nbf" claims.
+	Leeway time.Duration
+
+	// ErrorHandler writes the response for requests whose token was rejected. Defaults to DefaultErrorHandler.
+	ErrorHandler func(http.ResponseWriter, *http.Request, error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Verifier checks tokens against a Config. It keeps the fetched keys cached, so it should be created once per
+// execution environment.
+type Verifier struct {
+	config Config
+	cache  *keyCache
+}
+
+// NewVerifier creates a Verifier for the given configuration.
+func NewVerifier(config Config) *Verifier {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Hour
+	}
+
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = DefaultAlgorithms
+	}
+
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultErrorHandler
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Verifier{
+		config: config,
+		cache:  newKeyCache(config.Keys, config.CacheTTL, config.Now),
+	}
+}
+
+// Verify checks the signature and claims of the raw token and returns its claims. It returns ErrMissingKeySource when
+// the configuration has no Keys.
+func (v *Verifier) Verify(ctx context.Context, raw string) (Claims, error) {
+	if v.config.Keys == nil {
+		return nil, ErrMissingKeySource
+	}
+
+	t, err := parseToken(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(v.config.Algorithms, t.header.Alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	key, err := v.cache.key(ctx, t.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.jwk.Alg != "" && key.jwk.Alg != t.header.Alg {
+		return nil, ErrInvalidSignature
+	}
+
+	if err := t.verifySignature(key.key); err != nil {
+		return nil, err
+	}
+
+	if err := v.validate(t.claims); err != nil {
+		return nil, err
+	}
+
+	return t.claims, nil
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := v.config.Now()
+
+	exp, ok := claims.ExpiresAt()
+	if !ok || !now.Before(exp.Add(v.config.Leeway)) {
+		return ErrTokenExpired
+	}
+
+	if nbf, ok := claims.NotBefore(); ok && now.Add(v.config.Leeway).Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+
+	if v.config.Issuer != "" && claims.Issuer() != v.config.Issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(v.config.Audience) > 0 && !slices.ContainsFunc(claims.Audience(), func(aud string) bool {
+		return slices.Contains(v.config.Audience, aud)
+	}) {
+		return ErrInvalidAudience
+	}
+
+	if !claims.HasScopes(v.config.Scopes...) {
+		return ErrInsufficientScope
+	}
+
+	return nil
+}
+
+// Middleware returns an http.Handler middleware that verifies the bearer token of every request and stores its
+// claims in the request context, where they can be read with ClaimsFrom.
+func Middleware(config Config) func(http.Handler) http.Handler {
+	v := NewVerifier(config)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := bearerToken(r)
+			if !ok {
+				v.config.ErrorHandler(w, r, ErrMissingToken)
+				return
+			}
+
+			claims, err := v.Verify(r.Context(), raw)
+			if err != nil {
+				v.config.ErrorHandler(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// DefaultErrorHandler answers with a 403 status when the token lacks scopes, with a 500 status when keys are missing
+// or could not be fetched and with a 401 status otherwise.
+func DefaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrInsufficientScope):
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	case errors.Is(err, ErrFetchingKeys), errors.Is(err, ErrMissingKeySource):
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	case errors.Is(err, ErrMissingToken):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	default:
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+
+	scheme, raw, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	raw = strings.TrimSpace(raw)
+
+	return raw, raw != ""
+}
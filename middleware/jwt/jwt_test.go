@@ -0,0 +1,310 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Unix(1700000000, 0)
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, map[string]any{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	signed := encodeSegment(t, map[string]any{"alg": "ES256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func rsaJWK(key *rsa.PrivateKey, kid string) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":   "user-42",
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"api", "other"},
+		"exp":   now.Add(time.Hour).Unix(),
+		"scope": "read write",
+	}
+}
+
+func serve(cfg Config, token string) (*httptest.ResponseRecorder, Claims) {
+	var got Claims
+
+	h := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ClaimsFrom(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec, got
+}
+
+func TestMiddleware(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecJWK := JWK{
+		Kty: "EC",
+		Kid: "ec",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()),
+	}
+
+	cfg := Config{
+		Keys:     StaticKeySource(JWKS{Keys: []JWK{rsaJWK(rsaKey, "rsa"), ecJWK}}),
+		Issuer:   "https://issuer.example.com",
+		Audience: []string{"api"},
+		Scopes:   []string{"read"},
+		Now:      func() time.Time { return now },
+	}
+
+	t.Run("should store verified claims in the request context", func(t *testing.T) {
+		rec, claims := serve(cfg, signRS256(t, rsaKey, "rsa", validClaims()))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user-42", claims.Subject())
+		assert.Equal(t, []string{"read", "write"}, claims.Scopes())
+	})
+
+	t.Run("should verify ecdsa signatures", func(t *testing.T) {
+		rec, _ := serve(cfg, signES256(t, ecKey, "ec", validClaims()))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should reject missing token", func(t *testing.T) {
+		rec, _ := serve(cfg, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("should reject tampered token", func(t *testing.T) {
+		parts := strings.Split(signRS256(t, rsaKey, "rsa", validClaims()), ".")
+
+		claims := validClaims()
+		claims["sub"] = "admin"
+		parts[1] = encodeSegment(t, claims)
+
+		rec, _ := serve(cfg, strings.Join(parts, "."))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should reject invalid claims", func(t *testing.T) {
+		cases := map[string]func(map[string]any){
+			"expired":  func(c map[string]any) { c["exp"] = now.Add(-time.Minute).Unix() },
+			"issuer":   func(c map[string]any) { c["iss"] = "https://evil.example.com" },
+			"audience": func(c map[string]any) { c["aud"] = "other" },
+		}
+
+		for name, mutate := range cases {
+			claims := validClaims()
+			mutate(claims)
+
+			rec, _ := serve(cfg, signRS256(t, rsaKey, "rsa", claims))
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+		}
+	})
+
+	t.Run("should reject insufficient scope", func(t *testing.T) {
+		claims := validClaims()
+		claims["scope"] = "write"
+
+		rec, _ := serve(cfg, signRS256(t, rsaKey, "rsa", claims))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("should refetch keys on unknown key id", func(t *testing.T) {
+		oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		clock := now
+		fetches := 0
+		keys := []JWK{rsaJWK(oldKey, "old")}
+
+		v := NewVerifier(Config{
+			Keys: KeySourceFunc(func(context.Context) (JWKS, error) {
+				fetches++
+				return JWKS{Keys: keys}, nil
+			}),
+			Now: func() time.Time { return clock },
+		})
+
+		_, err = v.Verify(context.Background(), signRS256(t, oldKey, "old", validClaims()))
+		require.NoError(t, err)
+
+		_, err = v.Verify(context.Background(), signRS256(t, oldKey, "old", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, 1, fetches)
+
+		keys = append(keys, rsaJWK(newKey, "new"))
+		clock = clock.Add(2 * time.Minute)
+
+		_, err = v.Verify(context.Background(), signRS256(t, newKey, "new", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, 2, fetches)
+	})
+
+	t.Run("should keep serving cached keys when a refresh fails", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		clock := now
+		fetches := 0
+		down := false
+
+		v := NewVerifier(Config{
+			Keys: KeySourceFunc(func(context.Context) (JWKS, error) {
+				fetches++
+
+				if down {
+					return JWKS{}, errors.New("down")
+				}
+
+				return JWKS{Keys: []JWK{rsaJWK(key, "k1")}}, nil
+			}),
+			CacheTTL: 10 * time.Minute,
+			Now:      func() time.Time { return clock },
+		})
+
+		token := signRS256(t, key, "k1", validClaims())
+
+		_, err = v.Verify(context.Background(), token)
+		require.NoError(t, err)
+
+		down = true
+		clock = clock.Add(11 * time.Minute)
+
+		_, err = v.Verify(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fetches)
+
+		_, err = v.Verify(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fetches, "should back off before fetching again")
+
+		down = false
+		clock = clock.Add(time.Minute)
+
+		_, err = v.Verify(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, 3, fetches)
+	})
+
+	t.Run("should fail when no keys could be fetched", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		v := NewVerifier(Config{
+			Keys: KeySourceFunc(func(context.Context) (JWKS, error) {
+				return JWKS{}, errors.New("down")
+			}),
+			Now: func() time.Time { return now },
+		})
+
+		_, err = v.Verify(context.Background(), signRS256(t, key, "k1", validClaims()))
+		assert.ErrorIs(t, err, ErrFetchingKeys)
+	})
+
+	t.Run("should fail without key source", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = NewVerifier(Config{}).Verify(context.Background(), signRS256(t, key, "k1", validClaims()))
+		assert.ErrorIs(t, err, ErrMissingKeySource)
+
+		rec, _ := serve(Config{}, signRS256(t, key, "k1", validClaims()))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("should fetch keys over http", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(JWKS{Keys: []JWK{rsaJWK(key, "k1")}})
+		}))
+		defer srv.Close()
+
+		v := NewVerifier(Config{
+			Keys: HTTPKeySource(srv.URL, srv.Client()),
+			Now:  func() time.Time { return now },
+		})
+
+		claims, err := v.Verify(context.Background(), signRS256(t, key, "k1", validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "user-42", claims.Subject())
+	})
+}
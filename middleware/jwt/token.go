@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+type token struct {
+	header    header
+	claims    Claims
+	signed    string
+	signature []byte
+}
+
+// DefaultAlgorithms lists the signing algorithms accepted when none are configured.
+var DefaultAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+var hashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+func parseToken(raw string) (token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return token{}, ErrMalformedToken
+	}
+
+	var t token
+
+	if err := decodeSegment(parts[0], &t.header); err != nil {
+		return token{}, err
+	}
+
+	if err := decodeSegment(parts[1], &t.claims); err != nil {
+		return token{}, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return token{}, errors.Join(err, ErrMalformedToken)
+	}
+
+	t.signed = parts[0] + "." + parts[1]
+	t.signature = sig
+
+	return t, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errors.Join(err, ErrMalformedToken)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	if err := dec.Decode(v); err != nil {
+		return errors.Join(err, ErrMalformedToken)
+	}
+
+	return nil
+}
+
+// verifySignature checks the token signature with the given key according to the token algorithm.
+func (t token) verifySignature(key crypto.PublicKey) error {
+	alg := t.header.Alg
+
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, []byte(t.signed), t.signature) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	}
+
+	if len(alg) != 5 {
+		return ErrUnsupportedAlgorithm
+	}
+
+	hash, ok := hashes[alg[2:]]
+	if !ok {
+		return ErrUnsupportedAlgorithm
+	}
+
+	h := hash.New()
+	h.Write([]byte(t.signed))
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(pub, hash, digest, t.signature) != nil {
+			return ErrInvalidSignature
+		}
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPSS(pub, hash, digest, t.signature, nil) != nil {
+			return ErrInvalidSignature
+		}
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrInvalidSignature
+		}
+
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(t.signature) != 2*size {
+			return ErrInvalidSignature
+		}
+
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package params
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	paramTag  = "param"
+	secretTag = "secret"
+)
+
+// field is a struct field loaded from a provider.
+type field struct {
+	index    []int
+	name     string
+	secret   bool
+	optional bool
+}
+
+// fields returns the fields of t tagged with "param" or "secret". The tag value is the parameter name or secret id,
+// optionally followed by ",optional".
+func fields(t reflect.Type) ([]field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, ErrInvalidTarget
+	}
+
+	var out []field
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		for _, tag := range []string{paramTag, secretTag} {
+			value, ok := sf.Tag.Lookup(tag)
+			if !ok {
+				continue
+			}
+
+			name, opts, _ := strings.Cut(value, ",")
+
+			out = append(out, field{
+				index:    sf.Index,
+				name:     name,
+				secret:   tag == secretTag,
+				optional: opts == "optional",
+			})
+		}
+	}
+
+	return out, nil
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// decode sets the raw value into v, parsing it according to the type of v. Structs, maps and slices other than
+// []byte are decoded as JSON.
+func decode(v reflect.Value, raw string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(raw))
+			return nil
+		}
+
+		return json.Unmarshal([]byte(raw), v.Addr().Interface())
+	case reflect.Struct, reflect.Map, reflect.Pointer, reflect.Interface:
+		return json.Unmarshal([]byte(raw), v.Addr().Interface())
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
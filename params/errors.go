@@ -0,0 +1,11 @@
+package params
+
+import "errors"
+
+var (
+	ErrMissingProvider = errors.New("params: missing provider")
+	ErrMissingValue    = errors.New("params: missing value")
+	ErrDecodingValue   = errors.New("params: decoding value")
+	ErrInvalidTarget   = errors.New("params: target must be a struct")
+	ErrFetchingValue   = errors.New("params: fetching value")
+)
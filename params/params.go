@@ -0,0 +1,196 @@
+// Package params loads parameters and secrets into a typed struct, keeps them cached and refreshes them between
+// invocations, exposing them to handlers through the context.
+//
+// Struct fields are loaded from the tag `param:"name"` through the Parameters provider, or `secret:"id"` through the
+// Secrets provider. Append ",optional" to the tag to leave the field at its zero value when the value does not exist.
+package params
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Drafteame/engine"
+	"github.com/Drafteame/engine/internal/refresh"
+)
+
+// DefaultTTL is how long loaded values are considered fresh when Config.TTL is zero.
+const DefaultTTL = 5 * time.Minute
+
+// Provider fetches raw values by name. Names without a value are left out of the result.
+type Provider interface {
+	Get(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func(ctx context.Context, names []string) (map[string]string, error)
+
+// Get implements Provider.
+func (f ProviderFunc) Get(ctx context.Context, names []string) (map[string]string, error) {
+	return f(ctx, names)
+}
+
+// Config is the configuration of a Loader.
+type Config struct {
+	// Parameters provides the values of the fields tagged with "param".
+	Parameters Provider
+
+	// Secrets provides the values of the fields tagged with "secret". Defaults to Parameters.
+	Secrets Provider
+
+	// TTL is how long loaded values are fresh. Stale values keep being served while they are refreshed in the
+	// background. Defaults to DefaultTTL.
+	TTL time.Duration
+
+	// OnError is called when a background refresh fails. The previous values keep being served.
+	OnError func(error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Loader loads values into a struct of type C. It should be created once per execution environment, usually at init.
+type Loader[C any] struct {
+	config Config
+	fields []field
+	value  *refresh.Value[*C]
+}
+
+// NewLoader creates a Loader for C, which must be a struct.
+func NewLoader[C any](config Config) (*Loader[C], error) {
+	fs, err := fields(reflect.TypeFor[C]())
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Secrets == nil {
+		config.Secrets = config.Parameters
+	}
+
+	for _, f := range fs {
+		if (f.secret && config.Secrets == nil) || (!f.secret && config.Parameters == nil) {
+			return nil, ErrMissingProvider
+		}
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	l := &Loader[C]{config: config, fields: fs}
+
+	l.value = refresh.New(refresh.Config[*C]{
+		Fetch:   l.fetch,
+		MaxAge:  config.TTL,
+		OnError: config.OnError,
+		Now:     config.Now,
+	})
+
+	return l, nil
+}
+
+// Load returns the current values, loading them synchronously the first time. Once loaded, stale values are returned
+// immediately while a refresh runs in the background. The returned struct must not be modified.
+func (l *Loader[C]) Load(ctx context.Context) (*C, error) {
+	return l.value.Get(ctx)
+}
+
+// Refresh loads the values synchronously and returns them.
+func (l *Loader[C]) Refresh(ctx context.Context) (*C, error) {
+	return l.value.Refresh(ctx)
+}
+
+func (l *Loader[C]) fetch(ctx context.Context) (*C, error) {
+	var paramNames, secretNames []string
+
+	for _, f := range l.fields {
+		if f.secret {
+			secretNames = append(secretNames, f.name)
+		} else {
+			paramNames = append(paramNames, f.name)
+		}
+	}
+
+	params, err := get(ctx, l.config.Parameters, paramNames)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := get(ctx, l.config.Secrets, secretNames)
+	if err != nil {
+		return nil, err
+	}
+
+	c := new(C)
+	v := reflect.ValueOf(c).Elem()
+
+	for _, f := range l.fields {
+		values := params
+		if f.secret {
+			values = secrets
+		}
+
+		raw, ok := values[f.name]
+		if !ok {
+			if f.optional {
+				continue
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrMissingValue, f.name)
+		}
+
+		if err := decode(v.FieldByIndex(f.index), raw); err != nil {
+			return nil, errors.Join(fmt.Errorf("%w: %s", ErrDecodingValue, f.name), err)
+		}
+	}
+
+	return c, nil
+}
+
+func get(ctx context.Context, p Provider, names []string) (map[string]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	values, err := p.Get(ctx, names)
+	if err != nil {
+		return nil, errors.Join(ErrFetchingValue, err)
+	}
+
+	return values, nil
+}
+
+type contextKey[C any] struct{}
+
+// NewContext returns a copy of ctx carrying the given values.
+func NewContext[C any](ctx context.Context, c *C) context.Context {
+	return context.WithValue(ctx, contextKey[C]{}, c)
+}
+
+// From returns the values of type C stored in ctx by the Decorator.
+func From[C any](ctx context.Context) (*C, bool) {
+	c, ok := ctx.Value(contextKey[C]{}).(*C)
+	return c, ok
+}
+
+// Decorator is a decorator that loads the values of the loader on every invocation, refreshing them when stale, and
+// stores them in the context for From.
+func Decorator[T, R, C any](loader *Loader[C]) engine.Decorator[T, R] {
+	return func(handler engine.Handler[T, R]) engine.Handler[T, R] {
+		return func(ctx context.Context, evt T) (R, error) {
+			c, err := loader.Load(ctx)
+			if err != nil {
+				var zero R
+				return zero, err
+			}
+
+			return handler(NewContext(ctx, c), evt)
+		}
+	}
+}
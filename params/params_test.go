@@ -0,0 +1,283 @@
+package params
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testengine "github.com/Drafteame/engine/test/engine"
+)
+
+type appConfig struct {
+	DatabaseURL string            `secret:"prod/db-url"`
+	Timeout     time.Duration     `param:"/app/timeout"`
+	MaxItems    int               `param:"/app/max-items"`
+	Debug       bool              `param:"/app/debug,optional"`
+	Limits      map[string]int    `param:"/app/limits"`
+	Labels      map[string]string `param:"/app/labels,optional"`
+	ignored     string
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func staticProvider(calls *atomic.Int32, values map[string]string) Provider {
+	return ProviderFunc(func(_ context.Context, names []string) (map[string]string, error) {
+		calls.Add(1)
+
+		out := make(map[string]string)
+
+		for _, n := range names {
+			if v, ok := values[n]; ok {
+				out[n] = v
+			}
+		}
+
+		return out, nil
+	})
+}
+
+func TestLoader(t *testing.T) {
+	values := map[string]string{
+		"prod/db-url":    "postgres://db",
+		"/app/timeout":   "3s",
+		"/app/max-items": "50",
+		"/app/limits":    `{"free":10}`,
+	}
+
+	t.Run("should load values into the struct", func(t *testing.T) {
+		var calls atomic.Int32
+
+		loader, err := NewLoader[appConfig](Config{Parameters: staticProvider(&calls, values)})
+		require.NoError(t, err)
+
+		c, err := loader.Load(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "postgres://db", c.DatabaseURL)
+		assert.Equal(t, 3*time.Second, c.Timeout)
+		assert.Equal(t, 50, c.MaxItems)
+		assert.False(t, c.Debug)
+		assert.Equal(t, map[string]int{"free": 10}, c.Limits)
+		assert.Empty(t, c.ignored)
+	})
+
+	t.Run("should fail on missing and invalid values", func(t *testing.T) {
+		var calls atomic.Int32
+
+		loader, err := NewLoader[appConfig](Config{Parameters: staticProvider(&calls, map[string]string{})})
+		require.NoError(t, err)
+
+		_, err = loader.Load(context.Background())
+		assert.ErrorIs(t, err, ErrMissingValue)
+
+		invalid := map[string]string{"prod/db-url": "x", "/app/timeout": "soon", "/app/max-items": "1", "/app/limits": "{}"}
+
+		loader, err = NewLoader[appConfig](Config{Parameters: staticProvider(&calls, invalid)})
+		require.NoError(t, err)
+
+		_, err = loader.Load(context.Background())
+		assert.ErrorIs(t, err, ErrDecodingValue)
+		assert.ErrorContains(t, err, "/app/timeout")
+	})
+
+	t.Run("should require a provider for every tag", func(t *testing.T) {
+		_, err := NewLoader[appConfig](Config{})
+		assert.ErrorIs(t, err, ErrMissingProvider)
+
+		_, err = NewLoader[string](Config{})
+		assert.ErrorIs(t, err, ErrInvalidTarget)
+	})
+
+	t.Run("should serve stale values while refreshing in the background", func(t *testing.T) {
+		var (
+			calls   atomic.Int32
+			current atomic.Pointer[map[string]string]
+		)
+
+		clock := &fakeClock{now: time.Now()}
+		current.Store(&values)
+
+		provider := ProviderFunc(func(ctx context.Context, names []string) (map[string]string, error) {
+			return staticProvider(&calls, *current.Load()).Get(ctx, names)
+		})
+
+		loader, err := NewLoader[appConfig](Config{Parameters: provider, TTL: time.Minute, Now: clock.Now})
+		require.NoError(t, err)
+
+		first, err := loader.Load(context.Background())
+		require.NoError(t, err)
+
+		clock.now = clock.now.Add(30 * time.Second)
+
+		c, _ := loader.Load(context.Background())
+		assert.Same(t, first, c)
+		assert.Equal(t, int32(2), calls.Load())
+
+		current.Store(&map[string]string{
+			"prod/db-url":    "postgres://new",
+			"/app/timeout":   "1s",
+			"/app/max-items": "5",
+			"/app/limits":    "{}",
+		})
+
+		clock.now = clock.now.Add(time.Minute)
+
+		c, _ = loader.Load(context.Background())
+		assert.Same(t, first, c)
+
+		assert.Eventually(t, func() bool {
+			c, _ := loader.Load(context.Background())
+			return c.DatabaseURL == "postgres://new"
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should expose values through the context", func(t *testing.T) {
+		var calls atomic.Int32
+
+		loader, err := NewLoader[appConfig](Config{Parameters: staticProvider(&calls, values)})
+		require.NoError(t, err)
+
+		handler := func(ctx context.Context, _ string) (string, error) {
+			c, ok := From[appConfig](ctx)
+			if !ok {
+				return "", errors.New("missing config")
+			}
+
+			return c.DatabaseURL, nil
+		}
+
+		res, err := testengine.New(context.Background(), "hello", handler).
+			Use(Decorator[string, string](loader)).
+			Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, "postgres://db", res)
+	})
+}
+
+func TestProviders(t *testing.T) {
+	t.Run("should read environment variables", func(t *testing.T) {
+		t.Setenv("APP_PROD_DB_URL", "postgres://env")
+
+		values, err := Env("APP_").Get(context.Background(), []string{"prod/db-url", "/missing"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"prod/db-url": "postgres://env"}, values)
+	})
+
+	t.Run("should read json files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "params.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"/app/timeout":"3s","/app/limits":{"free":10}}`), 0o600))
+
+		values, err := File(path).Get(context.Background(), []string{"/app/timeout", "/app/limits", "/missing"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"/app/timeout": "3s", "/app/limits": `{"free":10}`}, values)
+	})
+
+	t.Run("should read through the lambda extension", func(t *testing.T) {
+		t.Setenv("AWS_SESSION_TOKEN", "session")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "session", r.Header.Get("X-Aws-Parameters-Secrets-Token"))
+
+			switch {
+			case r.URL.Path == "/systemsmanager/parameters/get" && r.URL.Query().Get("name") == "/app/timeout":
+				assert.Equal(t, "true", r.URL.Query().Get("withDecryption"))
+				_, _ = w.Write([]byte(`{"Parameter":{"Name":"/app/timeout","Value":"3s"}}`))
+			case r.URL.Path == "/secretsmanager/get" && r.URL.Query().Get("secretId") == "prod/db-url":
+				_, _ = w.Write([]byte(`{"SecretString":"postgres://ext"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		t.Setenv("PARAMETERS_SECRETS_EXTENSION_HTTP_PORT", u.Port())
+
+		params, err := ExtensionParameters(nil).Get(context.Background(), []string{"/app/timeout", "/missing"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"/app/timeout": "3s"}, params)
+
+		secrets, err := ExtensionSecrets(srv.Client()).Get(context.Background(), []string{"prod/db-url"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"prod/db-url": "postgres://ext"}, secrets)
+	})
+
+	t.Run("should batch ssm requests", func(t *testing.T) {
+		var batches [][]string
+
+		client := ssmClientFunc(func(_ context.Context, names []string) (map[string]string, error) {
+			batches = append(batches, names)
+
+			out := make(map[string]string)
+			for _, n := range names {
+				out[n] = strings.ToUpper(n)
+			}
+
+			return out, nil
+		})
+
+		names := make([]string, 12)
+		for i := range names {
+			names[i] = string(rune('a' + i))
+		}
+
+		values, err := SSM(client).Get(context.Background(), names)
+
+		require.NoError(t, err)
+		assert.Len(t, values, 12)
+		assert.Len(t, batches, 2)
+		assert.Len(t, batches[1], 2)
+	})
+
+	t.Run("should fetch secrets manager secrets", func(t *testing.T) {
+		errDenied := errors.New("access denied")
+
+		client := secretsClientFunc(func(_ context.Context, id string) (string, bool, error) {
+			switch id {
+			case "denied":
+				return "", false, errDenied
+			case "missing":
+				return "", false, nil
+			default:
+				return "secret-" + id, true, nil
+			}
+		})
+
+		values, err := SecretsManager(client).Get(context.Background(), []string{"a", "missing"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "secret-a"}, values)
+
+		_, err = SecretsManager(client).Get(context.Background(), []string{"a", "denied"})
+		assert.ErrorIs(t, err, errDenied)
+	})
+}
+
+type ssmClientFunc func(context.Context, []string) (map[string]string, error)
+
+func (f ssmClientFunc) GetParameters(ctx context.Context, names []string) (map[string]string, error) {
+	return f(ctx, names)
+}
+
+type secretsClientFunc func(context.Context, string) (string, bool, error)
+
+func (f secretsClientFunc) GetSecretValue(ctx context.Context, id string) (string, bool, error) {
+	return f(ctx, id)
+}
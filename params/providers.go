@@ -0,0 +1,257 @@
+package params
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultExtensionPort is the port of the AWS Parameters and Secrets Lambda Extension when
+	// PARAMETERS_SECRETS_EXTENSION_HTTP_PORT is not set.
+	DefaultExtensionPort = "2773"
+
+	// ssmBatchSize is the maximum number of names accepted by a GetParameters call.
+	ssmBatchSize = 10
+)
+
+// Env returns a provider reading environment variables, for local work. Names are turned into variable names by
+// trimming leading slashes, replacing "/", "-" and "." with underscores, upper casing them and adding the prefix, so
+// "/myapp/db-url" with the prefix "APP_" is read from APP_MYAPP_DB_URL.
+func Env(prefix string) Provider {
+	replacer := strings.NewReplacer("/", "_", "-", "_", ".", "_")
+
+	return ProviderFunc(func(_ context.Context, names []string) (map[string]string, error) {
+		values := make(map[string]string, len(names))
+
+		for _, name := range names {
+			key := prefix + strings.ToUpper(replacer.Replace(strings.TrimLeft(name, "/")))
+
+			if v, ok := os.LookupEnv(key); ok {
+				values[name] = v
+			}
+		}
+
+		return values, nil
+	})
+}
+
+// File returns a provider reading a JSON object file mapping names to values, for local work. Values that are not
+// strings are passed on as JSON.
+func File(path string) Provider {
+	return ProviderFunc(func(_ context.Context, names []string) (map[string]string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]string, len(names))
+
+		for _, name := range names {
+			raw, ok := doc[name]
+			if !ok {
+				continue
+			}
+
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				values[name] = s
+			} else {
+				values[name] = string(raw)
+			}
+		}
+
+		return values, nil
+	})
+}
+
+// HTTPClient sends the requests of the extension providers. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// ExtensionParameters returns a provider reading SSM parameters, decrypted, through the local HTTP cache of the AWS
+// Parameters and Secrets Lambda Extension. The client defaults to http.DefaultClient.
+func ExtensionParameters(client HTTPClient) Provider {
+	return extensionProvider(client, "/systemsmanager/parameters/get", "name", func(body []byte) (string, error) {
+		var out struct {
+			Parameter struct {
+				Value string `json:"Value"`
+			} `json:"Parameter"`
+		}
+
+		err := json.Unmarshal(body, &out)
+
+		return out.Parameter.Value, err
+	}, url.Values{"withDecryption": {"true"}})
+}
+
+// ExtensionSecrets returns a provider reading Secrets Manager secret strings through the local HTTP cache of the AWS
+// Parameters and Secrets Lambda Extension. The client defaults to http.DefaultClient.
+func ExtensionSecrets(client HTTPClient) Provider {
+	return extensionProvider(client, "/secretsmanager/get", "secretId", func(body []byte) (string, error) {
+		var out struct {
+			SecretString string `json:"SecretString"`
+		}
+
+		err := json.Unmarshal(body, &out)
+
+		return out.SecretString, err
+	}, nil)
+}
+
+func extensionProvider(client HTTPClient, path, param string, parse func([]byte) (string, error), query url.Values) Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return ProviderFunc(func(ctx context.Context, names []string) (map[string]string, error) {
+		port := os.Getenv("PARAMETERS_SECRETS_EXTENSION_HTTP_PORT")
+		if port == "" {
+			port = DefaultExtensionPort
+		}
+
+		values := make(map[string]string, len(names))
+
+		for _, name := range names {
+			q := url.Values{param: {name}}
+			for k, v := range query {
+				q[k] = v
+			}
+
+			u := url.URL{Scheme: "http", Host: "localhost:" + port, Path: path, RawQuery: q.Encode()}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Aws-Parameters-Secrets-Token", os.Getenv("AWS_SESSION_TOKEN"))
+
+			body, found, err := doExtensionRequest(client, req)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			if !found {
+				continue
+			}
+
+			v, err := parse(body)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			values[name] = v
+		}
+
+		return values, nil
+	})
+}
+
+func doExtensionRequest(client HTTPClient, req *http.Request) ([]byte, bool, error) {
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, false, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, true, nil
+}
+
+// SSMClient reads decrypted parameters from SSM Parameter Store. It is usually an adapter over the GetParameters
+// operation of the AWS SDK client, returning the values of the parameters found.
+type SSMClient interface {
+	GetParameters(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// SSM returns a provider reading parameters through the given client, in batches of ten names.
+func SSM(client SSMClient) Provider {
+	return ProviderFunc(func(ctx context.Context, names []string) (map[string]string, error) {
+		values := make(map[string]string, len(names))
+
+		for start := 0; start < len(names); start += ssmBatchSize {
+			batch, err := client.GetParameters(ctx, names[start:min(start+ssmBatchSize, len(names))])
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range batch {
+				values[k] = v
+			}
+		}
+
+		return values, nil
+	})
+}
+
+// SecretsManagerClient reads secrets from Secrets Manager. It is usually an adapter over the GetSecretValue operation
+// of the AWS SDK client, reporting whether the secret exists.
+type SecretsManagerClient interface {
+	GetSecretValue(ctx context.Context, secretID string) (string, bool, error)
+}
+
+// SecretsManager returns a provider reading secrets through the given client, fetching them concurrently.
+func SecretsManager(client SecretsManagerClient) Provider {
+	return ProviderFunc(func(ctx context.Context, names []string) (map[string]string, error) {
+		var (
+			mu     sync.Mutex
+			wg     sync.WaitGroup
+			values = make(map[string]string, len(names))
+			errs   = make([]error, len(names))
+		)
+
+		for i, name := range names {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				v, ok, err := client.GetSecretValue(ctx, name)
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: %w", name, err)
+					return
+				}
+
+				if ok {
+					mu.Lock()
+					values[name] = v
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+
+		return values, nil
+	})
+}
@@ -0,0 +1,233 @@
+// Package redact removes sensitive data from values before they are logged.
+package redact
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultReplacement is the text that replaces redacted strings when Config.Replacement is empty.
+const DefaultReplacement = "[REDACTED]"
+
+// DefaultHeaders are the header names redacted by Default.
+var DefaultHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Amz-Security-Token",
+}
+
+// Preset is implemented by events that know which of their fields hold sensitive data.
+type Preset interface {
+	Redaction() Config
+}
+
+// Config describes what to redact from the JSON representation of a value. Redacted strings are replaced with
+// Replacement while any other redacted value becomes null.
+type Config struct {
+	// Paths are dot separated JSON paths to redact, such as "requestContext.authorizer". A "*" segment matches any
+	// object key or array index.
+	Paths []string
+
+	// Headers are header names, matched case insensitively, redacted from any object whose key ends with "headers",
+	// such as "headers" or "multiValueHeaders".
+	Headers []string
+
+	// Patterns are matched against every string value, replacing the matching text.
+	Patterns []*regexp.Regexp
+
+	// Replacement is the text used instead of redacted strings. Defaults to DefaultReplacement.
+	Replacement string
+}
+
+// Default returns a configuration redacting the DefaultHeaders.
+func Default() Config {
+	return Config{Headers: slices.Clone(DefaultHeaders)}
+}
+
+// IsZero reports whether the configuration redacts nothing.
+func (c Config) IsZero() bool {
+	return len(c.Paths) == 0 && len(c.Headers) == 0 && len(c.Patterns) == 0
+}
+
+// Merge returns a configuration redacting everything redacted by c and others. The replacement of c wins.
+func (c Config) Merge(others ...Config) Config {
+	out := Config{
+		Paths:       slices.Clone(c.Paths),
+		Headers:     slices.Clone(c.Headers),
+		Patterns:    slices.Clone(c.Patterns),
+		Replacement: c.Replacement,
+	}
+
+	for _, o := range others {
+		out.Paths = append(out.Paths, o.Paths...)
+		out.Headers = append(out.Headers, o.Headers...)
+		out.Patterns = append(out.Patterns, o.Patterns...)
+		out.Replacement = cmp.Or(out.Replacement, o.Replacement)
+	}
+
+	return out
+}
+
+// Value returns a copy of v with the configured data redacted, obtained by a round trip through its JSON
+// representation. Fields that are not marshaled to JSON are left at their zero value, as is the whole copy when the
+// round trip fails, so nothing unredacted leaks.
+func Value[T any](v T, c Config) T {
+	if c.IsZero() {
+		return v
+	}
+
+	var out T
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return out
+	}
+
+	data, err = JSON(data, c)
+	if err != nil {
+		return out
+	}
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		var zero T
+		return zero
+	}
+
+	return out
+}
+
+// JSON redacts the given JSON document.
+func JSON(data []byte, c Config) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(newRedactor(c).walk(doc, nil, false))
+}
+
+// String replaces the text matching the configured patterns.
+func String(s string, c Config) string {
+	return newRedactor(c).string(s)
+}
+
+// Error returns an error whose message has the configured patterns redacted. It unwraps to err.
+func Error(err error, c Config) error {
+	if err == nil || len(c.Patterns) == 0 {
+		return err
+	}
+
+	return &redactedError{err: err, msg: String(err.Error(), c)}
+}
+
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+type redactor struct {
+	paths       [][]string
+	headers     map[string]bool
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+func newRedactor(c Config) redactor {
+	r := redactor{
+		paths:       make([][]string, 0, len(c.Paths)),
+		headers:     make(map[string]bool, len(c.Headers)),
+		patterns:    c.Patterns,
+		replacement: cmp.Or(c.Replacement, DefaultReplacement),
+	}
+
+	for _, p := range c.Paths {
+		r.paths = append(r.paths, strings.Split(p, "."))
+	}
+
+	for _, h := range c.Headers {
+		r.headers[strings.ToLower(h)] = true
+	}
+
+	return r
+}
+
+// walk redacts the decoded JSON value v found at path. header tells whether v is the value of a redacted header.
+func (r redactor) walk(v any, path []string, header bool) any {
+	if header || r.matches(path) {
+		return r.redact(v)
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		headers := len(path) > 0 && strings.HasSuffix(strings.ToLower(path[len(path)-1]), "headers")
+
+		for k, item := range val {
+			val[k] = r.walk(item, append(path, k), headers && r.headers[strings.ToLower(k)])
+		}
+
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = r.walk(item, append(path, strconv.Itoa(i)), false)
+		}
+
+		return val
+	case string:
+		return r.string(val)
+	default:
+		return v
+	}
+}
+
+// redact replaces v, keeping the shape of string lists so multi value headers still decode.
+func (r redactor) redact(v any) any {
+	switch val := v.(type) {
+	case string:
+		return r.replacement
+	case []any:
+		for i, item := range val {
+			val[i] = r.redact(item)
+		}
+
+		return val
+	default:
+		return nil
+	}
+}
+
+func (r redactor) matches(path []string) bool {
+	for _, p := range r.paths {
+		if len(p) != len(path) {
+			continue
+		}
+
+		if slices.EqualFunc(p, path, func(a, b string) bool { return a == "*" || a == b }) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r redactor) string(s string) string {
+	for _, p := range r.patterns {
+		s = p.ReplaceAllString(s, r.replacement)
+	}
+
+	return s
+}
@@ -0,0 +1,81 @@
+package redact
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	Context           struct {
+		Authorizer map[string]any `json:"authorizer"`
+		Count      int            `json:"count"`
+	} `json:"context"`
+	Items []map[string]string `json:"items"`
+}
+
+func TestValue(t *testing.T) {
+	t.Run("should return the value as is when nothing is configured", func(t *testing.T) {
+		req := request{Body: "secret"}
+		assert.Equal(t, req, Value(req, Config{}))
+	})
+
+	t.Run("should redact headers, paths and patterns", func(t *testing.T) {
+		req := request{
+			Headers:           map[string]string{"authorization": "Bearer abc", "accept": "*/*"},
+			MultiValueHeaders: map[string][]string{"Cookie": {"a=1", "b=2"}},
+			Body:              `{"email":"jane@example.com"}`,
+			Items:             []map[string]string{{"ssn": "123", "name": "jane"}},
+		}
+		req.Context.Authorizer = map[string]any{"sub": "user-1"}
+		req.Context.Count = 3
+
+		config := Default().Merge(Config{
+			Paths:    []string{"context.authorizer", "items.*.ssn"},
+			Patterns: []*regexp.Regexp{regexp.MustCompile(`[\w.]+@[\w.]+`)},
+		})
+
+		out := Value(req, config)
+
+		assert.Equal(t, DefaultReplacement, out.Headers["authorization"])
+		assert.Equal(t, "*/*", out.Headers["accept"])
+		assert.Equal(t, []string{DefaultReplacement, DefaultReplacement}, out.MultiValueHeaders["Cookie"])
+		assert.Equal(t, `{"email":"[REDACTED]"}`, out.Body)
+		assert.Nil(t, out.Context.Authorizer)
+		assert.Equal(t, 3, out.Context.Count)
+		assert.Equal(t, map[string]string{"ssn": DefaultReplacement, "name": "jane"}, out.Items[0])
+
+		assert.Equal(t, "Bearer abc", req.Headers["authorization"])
+	})
+
+	t.Run("should return the zero value when the round trip fails", func(t *testing.T) {
+		out := Value(map[string]any{"fn": func() {}}, Default())
+		assert.Nil(t, out)
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("should use the configured replacement", func(t *testing.T) {
+		out, err := JSON([]byte(`{"a":{"b":"c","n":12345678901234567890}}`), Config{Paths: []string{"a.b"}, Replacement: "***"})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a":{"b":"***","n":12345678901234567890}}`, string(out))
+	})
+}
+
+func TestError(t *testing.T) {
+	t.Run("should redact the message and keep the chain", func(t *testing.T) {
+		cause := errors.New("invalid token abc123")
+
+		err := Error(cause, Config{Patterns: []*regexp.Regexp{regexp.MustCompile(`token \w+`)}})
+
+		assert.Equal(t, "invalid [REDACTED]", err.Error())
+		assert.ErrorIs(t, err, cause)
+	})
+}